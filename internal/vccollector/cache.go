@@ -30,6 +30,18 @@ type hostState struct {
 	responseTime   time.Duration
 }
 
+type hostHistory struct {
+	bootTime       time.Time
+	reboots        int64
+	selInitialized bool
+	lastSelRecord  int64
+}
+
+type vmState struct {
+	bootTime time.Time
+	reboots  int64
+}
+
 type VcCache struct {
 	lastDCUpdate time.Time                          //nolint
 	lastCHUpdate time.Time                          //nolint
@@ -41,8 +53,10 @@ type VcCache struct {
 	dss          [][]*object.Datastore              //nolint
 	hosts        [][]*object.HostSystem             //nolint
 	hostStates   [][]hostState                      //nolint
+	hostHistory  map[string]*hostHistory            //nolint
 	nets         [][]object.NetworkReference        //nolint
 	vms          [][]*object.VirtualMachine         //nolint
+	vmStates     map[string]*vmState                //nolint
 }
 
 func (c *VcCollector) getDatacenters(ctx context.Context) error {
@@ -115,6 +129,22 @@ func (c *VcCollector) getAllDatacentersClustersAndHosts(ctx context.Context) err
 	}
 	c.lastCHUpdate = time.Now()
 
+	// forget history of hosts that no longer exist
+	if c.hostHistory == nil {
+		c.hostHistory = make(map[string]*hostHistory)
+	}
+	hostids := make(map[string]bool)
+	for i := range c.hosts {
+		for _, host := range c.hosts[i] {
+			hostids[host.Reference().Value] = true
+		}
+	}
+	for moid := range c.hostHistory {
+		if !hostids[moid] {
+			delete(c.hostHistory, moid)
+		}
+	}
+
 	return nil
 }
 
@@ -214,6 +244,22 @@ func (c *VcCollector) getAllDatacentersVMs(ctx context.Context) error {
 	}
 	c.lastVmUpdate = time.Now()
 
+	// forget states of VMs that no longer exist
+	if c.vmStates == nil {
+		c.vmStates = make(map[string]*vmState)
+	}
+	vmids := make(map[string]bool)
+	for i := range c.vms {
+		for _, vm := range c.vms[i] {
+			vmids[vm.Reference().Value] = true
+		}
+	}
+	for moid := range c.vmStates {
+		if !vmids[moid] {
+			delete(c.vmStates, moid)
+		}
+	}
+
 	return nil
 }
 
@@ -253,6 +299,26 @@ func (c *VcCollector) getHostStateIdx(dcindex, hostindex int) *hostState {
 	return &(c.hostStates[dcindex][hostindex])
 }
 
+func (c *VcCollector) getHostHistory(moid string) *hostHistory {
+	if c.hostHistory == nil {
+		c.hostHistory = make(map[string]*hostHistory)
+	}
+	if c.hostHistory[moid] == nil {
+		c.hostHistory[moid] = &hostHistory{}
+	}
+	return c.hostHistory[moid]
+}
+
+func (c *VcCollector) getVMState(moid string) *vmState {
+	if c.vmStates == nil {
+		c.vmStates = make(map[string]*vmState)
+	}
+	if c.vmStates[moid] == nil {
+		c.vmStates[moid] = &vmState{}
+	}
+	return c.vmStates[moid]
+}
+
 // GetNumberNotRespondingHosts returns the number of hosts connected but not responding
 // to esxcli commands
 func (c *VcCollector) GetNumberNotRespondingHosts() int {
@@ -310,3 +376,25 @@ func (h *hostState) isHostConnectedAndResponding(skipDuration time.Duration) boo
 func (h *hostState) isHostConnected() bool {
 	return !h.notConnected
 }
+
+// setBootTime keeps the host boot time counting reboots since the previous one seen
+func (h *hostHistory) setBootTime(bootTime time.Time) {
+	if !h.bootTime.IsZero() && bootTime.After(h.bootTime) {
+		h.reboots++
+	}
+	h.bootTime = bootTime
+}
+
+// setLastSelRecord keeps the last IPMI SEL record number seen in the host
+func (h *hostHistory) setLastSelRecord(record int64) {
+	h.selInitialized = true
+	h.lastSelRecord = record
+}
+
+// setBootTime keeps the VM boot time counting reboots since the previous one seen
+func (v *vmState) setBootTime(bootTime time.Time) {
+	if !v.bootTime.IsZero() && bootTime.After(v.bootTime) {
+		v.reboots++
+	}
+	v.bootTime = bootTime
+}
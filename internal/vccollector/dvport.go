@@ -0,0 +1,189 @@
+// This file contains vccollector methods to gather stats about distributed virtual ports
+//  (like portgroup port usage or per port state and counters)
+//
+// Author: Tesifonte Belda
+// License: The MIT License (MIT)
+
+package vccollector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/tesibelda/vcstat/pkg/govplus"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// dvpPortUsage holds the port usage counters of a distributed virtual portgroup
+type dvpPortUsage struct {
+	ports, used, blocked, linkDown int
+}
+
+// CollectNetDVPort gathers Distributed Virtual Port usage per portgroup and optionally
+// per port state and statistics
+func (c *VcCollector) CollectNetDVPort(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		ustags   = make(map[string]string)
+		usfields = make(map[string]interface{})
+		pttags   = make(map[string]string)
+		ptfields = make(map[string]interface{})
+		dvss     []object.NetworkReference
+		pgNames  map[string]string
+		usages   map[string]*dvpPortUsage
+		ports    []types.DistributedVirtualPort
+		t        time.Time
+		err      error
+		exit     bool
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get network DVPorts info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersNetworks(ctx); err != nil {
+		return fmt.Errorf("could not get network entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		// get DVS list and portgroup names by key
+		dvss = nil
+		pgNames = make(map[string]string)
+		for _, net := range c.nets[i] {
+			switch net.Reference().Type {
+			case "DistributedVirtualSwitch", "VmwareDistributedVirtualSwitch":
+				dvss = append(dvss, net)
+			case "DistributedVirtualPortgroup":
+				pgNames[net.Reference().Value] = networkName(net)
+			}
+		}
+
+		for _, net := range dvss {
+			dvs := object.NewDistributedVirtualSwitch(c.client.Client, net.Reference())
+			ports, err = dvs.FetchDVPorts(ctx, &types.DistributedVirtualSwitchPortCriteria{})
+			if err != nil {
+				if exit, err = govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not fetch ports of DVS %s: %w", networkName(net), err),
+				)
+				continue
+			}
+			t = time.Now()
+
+			pttags["dcname"] = dc.Name()
+			pttags["dvs"] = networkName(net)
+			pttags["vcenter"] = c.client.Client.URL().Host
+
+			usages = make(map[string]*dvpPortUsage)
+			for _, port := range ports {
+				if port.PortgroupKey == "" {
+					continue
+				}
+				usage, ok := usages[port.PortgroupKey]
+				if !ok {
+					usage = &dvpPortUsage{}
+					usages[port.PortgroupKey] = usage
+				}
+				connected := port.Connectee != nil && port.Connectee.ConnectedEntity != nil
+				var status *types.DVPortStatus
+				if port.State != nil {
+					status = port.State.RuntimeInfo
+				}
+				usage.ports++
+				if connected {
+					usage.used++
+				}
+				if status != nil && status.Blocked {
+					usage.blocked++
+				}
+				if connected && status != nil && !status.LinkUp {
+					usage.linkDown++
+				}
+
+				if !c.dvPortDetails {
+					continue
+				}
+				pttags["dvp"] = pgNames[port.PortgroupKey]
+				pttags["port_key"] = port.Key
+				addDVPortFields(ptfields, &port, status)
+
+				acc.AddFields("vcstat_net_dvport", ptfields, pttags, t)
+			}
+
+			for key, usage := range usages {
+				ustags["dcname"] = dc.Name()
+				ustags["dvp"] = pgNames[key]
+				ustags["dvs"] = networkName(net)
+				ustags["moid"] = key
+				ustags["vcenter"] = c.client.Client.URL().Host
+
+				usfields["num_ports"] = usage.ports
+				usfields["used_ports"] = usage.used
+				usfields["free_ports"] = usage.ports - usage.used
+				usfields["blocked_ports"] = usage.blocked
+				usfields["link_down_ports"] = usage.linkDown
+
+				acc.AddFields("vcstat_net_dvp_usage", usfields, ustags, t)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addDVPortFields adds distributed virtual port connection, state and statistics fields
+func addDVPortFields(
+	fields map[string]interface{},
+	port *types.DistributedVirtualPort,
+	status *types.DVPortStatus,
+) {
+	var stats types.DistributedVirtualSwitchPortStatistics
+
+	fields["name"] = port.Config.Name
+	fields["connected_entity"] = ""
+	fields["connectee_type"] = ""
+	fields["nic_key"] = ""
+	if connectee := port.Connectee; connectee != nil {
+		if connectee.ConnectedEntity != nil {
+			fields["connected_entity"] = connectee.ConnectedEntity.Value
+		}
+		fields["connectee_type"] = connectee.Type
+		fields["nic_key"] = connectee.NicKey
+	}
+	fields["link_up"] = status != nil && status.LinkUp
+	fields["blocked"] = status != nil && status.Blocked
+
+	if port.State != nil {
+		stats = port.State.Stats
+	}
+	fields["packets_in"] = stats.PacketsInUnicast + stats.PacketsInMulticast +
+		stats.PacketsInBroadcast
+	fields["packets_out"] = stats.PacketsOutUnicast + stats.PacketsOutMulticast +
+		stats.PacketsOutBroadcast
+	fields["bytes_in"] = stats.BytesInUnicast + stats.BytesInMulticast + stats.BytesInBroadcast
+	fields["bytes_out"] = stats.BytesOutUnicast + stats.BytesOutMulticast +
+		stats.BytesOutBroadcast
+	fields["packets_in_dropped"] = stats.PacketsInDropped
+	fields["packets_out_dropped"] = stats.PacketsOutDropped
+	fields["packets_in_exception"] = stats.PacketsInException
+	fields["packets_out_exception"] = stats.PacketsOutException
+}
+
+// networkName returns the name of a network entity from its inventory path
+func networkName(net object.NetworkReference) string {
+	if named, ok := net.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return net.Reference().Value
+}
@@ -7,6 +7,7 @@ package vccollector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -35,6 +36,7 @@ func (c *VcCollector) CollectHostInfo(
 		arefs              []types.ManagedObjectReference
 		host               *object.HostSystem
 		hostSt             *hostState
+		hostHs             *hostHistory
 		s                  *(types.HostListSummary)
 		r                  *(types.HostRuntimeInfo)
 		h                  *(types.HostHardwareSummary)
@@ -66,7 +68,21 @@ func (c *VcCollector) CollectHostInfo(
 		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
 
 		for _, refs := range chunks {
-			err = c.coll.Retrieve(ctx, refs, []string{"name", "summary", "vm", "datastore"}, &hsMos)
+			err = c.coll.Retrieve(
+				ctx,
+				refs,
+				[]string{
+					"name",
+					"summary",
+					"vm",
+					"datastore",
+					"hardware.systemInfo",
+					"hardware.biosInfo",
+					"config.powerSystemInfo",
+					"config.hyperThread",
+				},
+				&hsMos,
+			)
 			if err != nil {
 				if exit, err := govplus.IsHardQueryError(err); exit {
 					return err
@@ -108,6 +124,26 @@ func (c *VcCollector) CollectHostInfo(
 				hsfields["reboot_required"] = s.RebootRequired
 				hsfields["status"] = string(s.OverallStatus)
 				hsfields["status_code"] = hsCode
+				hostHs = c.getHostHistory(hsMo.Self.Value)
+				if r.BootTime != nil {
+					hostHs.setBootTime(*r.BootTime)
+					hsfields["boot_time"] = r.BootTime.Unix()
+					hsfields["uptime_seconds"] = int64(t.Sub(*r.BootTime).Seconds())
+				} else {
+					delete(hsfields, "boot_time")
+					delete(hsfields, "uptime_seconds")
+				}
+				hsfields["reboots"] = hostHs.reboots
+				if c.quickStats {
+					q := &s.QuickStats
+					hsfields["cpu_usage"] = q.OverallCpuUsage
+					hsfields["memory_usage"] = int64(q.OverallMemoryUsage) * (1024 * 1024)
+					hsfields["distributed_cpu_fairness"] = q.DistributedCpuFairness
+					hsfields["distributed_memory_fairness"] = q.DistributedMemoryFairness
+					if r.BootTime == nil {
+						hsfields["uptime_seconds"] = int64(q.Uptime)
+					}
+				}
 				if h != nil {
 					hsfields["memory_size"] = h.MemorySize
 					hsfields["num_cpus"] = h.NumCpuCores
@@ -115,6 +151,48 @@ func (c *VcCollector) CollectHostInfo(
 				}
 				hsfields["num_vms"] = len(hsMo.Vm)
 				hsfields["num_datastores"] = len(hsMo.Datastore)
+				hsfields["esxi_version"] = ""
+				hsfields["esxi_build"] = ""
+				hsfields["esxi_update_level"] = ""
+				if p := s.Config.Product; p != nil {
+					hsfields["esxi_version"] = p.Version
+					hsfields["esxi_build"] = p.Build
+					hsfields["esxi_update_level"] = p.PatchLevel
+				}
+				hsfields["vendor"] = ""
+				hsfields["model"] = ""
+				hsfields["serial_number"] = ""
+				hsfields["bios_version"] = ""
+				hsfields["bios_release_date"] = ""
+				if hw := hsMo.Hardware; hw != nil {
+					hsfields["vendor"] = hw.SystemInfo.Vendor
+					hsfields["model"] = hw.SystemInfo.Model
+					hsfields["serial_number"] = hostSerialNumber(&hw.SystemInfo)
+					if hw.BiosInfo != nil {
+						hsfields["bios_version"] = hw.BiosInfo.BiosVersion
+						if hw.BiosInfo.ReleaseDate != nil {
+							hsfields["bios_release_date"] = hw.BiosInfo.ReleaseDate.Format(
+								time.DateOnly,
+							)
+						}
+					}
+				}
+
+				hsfields["evc_mode"] = s.CurrentEVCModeKey
+				hsfields["power_policy"] = ""
+				hsfields["hyperthread_available"] = false
+				hsfields["hyperthread_active"] = false
+				hsfields["hyperthread_config"] = false
+				if cfg := hsMo.Config; cfg != nil {
+					if cfg.PowerSystemInfo != nil {
+						hsfields["power_policy"] = cfg.PowerSystemInfo.CurrentPolicy.ShortName
+					}
+					if ht := cfg.HyperThread; ht != nil {
+						hsfields["hyperthread_available"] = ht.Available
+						hsfields["hyperthread_active"] = ht.Active
+						hsfields["hyperthread_config"] = ht.Config
+					}
+				}
 
 				acc.AddFields("vcstat_host", hsfields, hstags, t)
 			}
@@ -127,19 +205,20 @@ func (c *VcCollector) CollectHostInfo(
 	return nil
 }
 
-// CollectHostHBA gathers host HBA info (like govc: storage core adapter list)
+// CollectHostHBA gathers host HBA info (like govc: storage core adapter list) and
+// Fibre Channel port details (like govc: storage san fc list)
 func (c *VcCollector) CollectHostHBA(
 	ctx context.Context,
 	acc telegraf.Accumulator,
 ) error {
 	var (
-		hbatags      = make(map[string]string)
-		hbafields    = make(map[string]interface{})
-		x            *esx.Executor
-		res          *esx.Response
-		hostSt       *hostState
-		startTime, t time.Time
-		err          error
+		hbatags                   = make(map[string]string)
+		hbafields                 = make(map[string]interface{})
+		x                         *esx.Executor
+		res                       *esx.Response
+		hostSt                    *hostState
+		startTime, fcStartTime, t time.Time
+		err                       error
 	)
 
 	if c.client == nil {
@@ -193,6 +272,18 @@ func (c *VcCollector) CollectHostHBA(
 					acc.AddFields("vcstat_host_hba", hbafields, hbatags, t)
 				}
 			}
+
+			// Fibre Channel ports details and statistics, errors are already reported and
+			// should not stop collecting HBA info from this host in the next intervals
+			fcStartTime = time.Now()
+			err = c.collectHostFC(ctx, acc, x, dc.Name(), c.getClusternameFromHost(i, host), host)
+			hostSt.sumResponseTime(time.Since(fcStartTime))
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+			}
+			t = time.Now()
 			if t.Sub(startTime) >= c.maxResponseDuration {
 				hostSt.setNotResponding(true)
 				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
@@ -206,6 +297,83 @@ func (c *VcCollector) CollectHostHBA(
 	return nil
 }
 
+// collectHostFC gathers host Fibre Channel port details and statistics using the given
+// esxcli executor (like govc: storage san fc list and storage san fc stats get)
+func (c *VcCollector) collectHostFC(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+	x *esx.Executor,
+	dcname, clustername string,
+	host *object.HostSystem,
+) error {
+	var (
+		fctags   = make(map[string]string)
+		fcfields = make(map[string]interface{})
+		stats    = make(map[string]esx.Values)
+		res      *esx.Response
+		t        time.Time
+		err      error
+	)
+
+	res, err = x.Run(ctx, []string{"storage", "san", "fc", "stats", "get"})
+	if err != nil {
+		hostExecutorRunAddError(acc, "storage san fc stats", host.Name(), err)
+		return err
+	}
+	for _, rv := range res.Values {
+		if len(rv) > 0 && len(rv["Adapter"]) > 0 {
+			stats[rv["Adapter"][0]] = rv
+		}
+	}
+
+	res, err = x.Run(ctx, []string{"storage", "san", "fc", "list"})
+	if err != nil {
+		hostExecutorRunAddError(acc, "storage san fc", host.Name(), err)
+		return err
+	}
+
+	t = time.Now()
+	for _, rv := range res.Values {
+		if len(rv) == 0 || len(rv["Adapter"]) == 0 {
+			continue
+		}
+		fctags["clustername"] = clustername
+		fctags["dcname"] = dcname
+		fctags["device"] = rv["Adapter"][0]
+		fctags["esxhostname"] = host.Name()
+		fctags["vcenter"] = c.client.Client.URL().Host
+
+		fcfields["node_name"] = rv.Value("NodeName")
+		fcfields["port_name"] = rv.Value("PortName")
+		fcfields["port_state"] = rv.Value("PortState")
+		fcfields["port_state_code"] = fcPortStateCode(rv.Value("PortState"))
+		fcfields["port_type"] = rv.Value("PortType")
+		fcfields["speed"] = rv.Value("Speed")
+		fcfields["model"] = rv.Value("ModelDescription")
+		if st, ok := stats[rv["Adapter"][0]]; ok {
+			for field, key := range fcStatsFields {
+				addEsxcliIntField(
+					acc,
+					fcfields,
+					field,
+					st,
+					key,
+					parseEsxcliInt,
+					"storage san fc stats",
+					host.Name(),
+				)
+			}
+		}
+
+		acc.AddFields("vcstat_host_fc", fcfields, fctags, t)
+		for k := range fcfields {
+			delete(fcfields, k)
+		}
+	}
+
+	return nil
+}
+
 // CollectHostNIC gathers host NIC info (like govc: host.esxcli network nic list)
 func (c *VcCollector) CollectHostNIC(
 	ctx context.Context,
@@ -562,6 +730,21 @@ func (c *VcCollector) getHostObjectFromReference(
 	return nil
 }
 
+// hostSerialNumber returns the host serial number or service tag
+func hostSerialNumber(info *types.HostSystemInfo) string {
+	if info.SerialNumber != "" {
+		return info.SerialNumber
+	}
+	for _, id := range info.OtherIdentifyingInfo {
+		if key := id.IdentifierType.GetElementDescription().Key; key == "ServiceTag" ||
+			key == "EnclosureSerialNumberTag" || key == "SerialNumberTag" {
+			return id.IdentifierValue
+		}
+	}
+
+	return ""
+}
+
 func hostExecutorNewAddError(acc telegraf.Accumulator, host string, err error) {
 	acc.AddError(
 		fmt.Errorf(
@@ -640,6 +823,107 @@ func hbaLinkStateCode(state string) int16 {
 	}
 }
 
+// addEsxcliBoolField adds the given esxcli key value as a bool field, leaving the field
+// out if the key is absent and reporting an error if the value is not a bool
+func addEsxcliBoolField(
+	acc telegraf.Accumulator,
+	fields map[string]interface{},
+	field string,
+	values esx.Values,
+	key string,
+	executor, host string,
+) {
+	delete(fields, field)
+	if len(values[key]) == 0 {
+		return
+	}
+	b, err := strconv.ParseBool(values[key][0])
+	if err != nil {
+		hostExecutorParseAddError(acc, executor, host, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	fields[field] = b
+}
+
+// parseEsxcliMiB parses an esxcli size in MiB returning it in bytes
+func parseEsxcliMiB(value string) (int64, error) {
+	i, err := parseEsxcliInt(value)
+	return i * (1024 * 1024), err
+}
+
+// fcStatsFields maps vcstat_host_fc counter fields to esxcli storage san fc stats keys
+var fcStatsFields = map[string]string{
+	"link_failures":    "LinkFailureCount",
+	"loss_of_sync":     "LossofSyncCount",
+	"loss_of_signal":   "LossofSignalCount",
+	"invalid_crc":      "InvalidCRCCount",
+	"invalid_tx_words": "InvalidTxWordCount",
+	"error_frames":     "ErrorFrames",
+	"dumped_frames":    "DumpedFrames",
+	"lip_count":        "LIPCount",
+	"nos_count":        "NOSCount",
+	"rx_frames":        "RxFrames",
+	"tx_frames":        "TxFrames",
+}
+
+// fcPortStateCode converts Fibre Channel Port State to int16 for easy alerting
+func fcPortStateCode(state string) int16 {
+	switch state {
+	case "ONLINE":
+		return 0
+	case "UNKNOWN":
+		return 1
+	case "LINK DOWN":
+		return 3
+	case "OFFLINE":
+		return 3
+	default:
+		return 1
+	}
+}
+
+// esxcliInt parses an esxcli integer value ignoring any unit suffix (ie 1024 KiB)
+// and returning 0 if it is not a number
+func esxcliInt(value string) int64 {
+	i, err := parseEsxcliInt(value)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// parseEsxcliInt parses an esxcli integer value ignoring any unit suffix (ie 1024 KiB)
+func parseEsxcliInt(value string) (int64, error) {
+	words := strings.Fields(value)
+	if len(words) == 0 {
+		return 0, errors.New("empty integer value")
+	}
+	return strconv.ParseInt(words[0], 10, 64)
+}
+
+// addEsxcliIntField adds the given esxcli key value as an integer field, leaving the field
+// out if the key is absent and reporting an error if the value is not a number
+func addEsxcliIntField(
+	acc telegraf.Accumulator,
+	fields map[string]interface{},
+	field string,
+	values esx.Values,
+	key string,
+	parse func(string) (int64, error),
+	executor, host string,
+) {
+	delete(fields, field)
+	if len(values[key]) == 0 {
+		return
+	}
+	i, err := parse(values[key][0])
+	if err != nil {
+		hostExecutorParseAddError(acc, executor, host, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	fields[field] = i
+}
+
 // nicLinkStatusCode converts LinkStatus to int16 for easy alerting
 func nicLinkStatusCode(state string) int16 {
 	switch state {
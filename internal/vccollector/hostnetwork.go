@@ -0,0 +1,326 @@
+// This file contains vccollector methods to gather stats about host network configuration
+//  (like TCP/IP stacks, DNS, routing or standard virtual switches)
+//
+// Author: Tesifonte Belda
+// License: The MIT License (MIT)
+
+package vccollector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/tesibelda/vcstat/pkg/govplus"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const defaultTcpipStack = "defaultTcpipStack"
+
+// CollectHostNetstack gathers host TCP/IP stacks DNS and routing configuration
+func (c *VcCollector) CollectHostNetstack(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		nstags   = make(map[string]string)
+		nsfields = make(map[string]interface{})
+		hsref    types.ManagedObjectReference
+		hsMos    []mo.HostSystem
+		arefs    []types.ManagedObjectReference
+		host     *object.HostSystem
+		hostSt   *hostState
+		stacks   []types.HostNetStackInstance
+		t        time.Time
+		err      error
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get host netstack info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		// get connected Host reference list and split it into chunks
+		arefs = nil
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnected() {
+				continue
+			}
+			arefs = append(arefs, host.Reference())
+		}
+		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
+
+		for _, refs := range chunks {
+			hsMos = nil
+			err = c.coll.Retrieve(
+				ctx,
+				refs,
+				[]string{
+					"name",
+					"config.network.dnsConfig",
+					"config.network.ipRouteConfig",
+					"config.network.netStackInstance",
+				},
+				&hsMos,
+			)
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not retrieve netstack info for host reference list: %w", err),
+				)
+				continue
+			}
+			t = time.Now()
+
+			for _, hsMo := range hsMos {
+				hsref = hsMo.Self.Reference()
+				if host = c.getHostObjectFromReference(i, &hsref); host == nil {
+					continue
+				}
+				if hsMo.Config == nil || hsMo.Config.Network == nil {
+					continue
+				}
+				network := hsMo.Config.Network
+
+				// older hosts have no netstack instances but the global config
+				stacks = network.NetStackInstance
+				if len(stacks) == 0 {
+					stacks = []types.HostNetStackInstance{{Key: defaultTcpipStack}}
+				}
+
+				nstags["clustername"] = c.getClusternameFromHost(i, host)
+				nstags["dcname"] = dc.Name()
+				nstags["esxhostname"] = hsMo.Name
+				nstags["moid"] = hsMo.Self.Value
+				nstags["vcenter"] = c.client.Client.URL().Host
+
+				for _, stack := range stacks {
+					dnsConfig, routeConfig := stack.DnsConfig, stack.IpRouteConfig
+					if stack.Key == defaultTcpipStack {
+						if dnsConfig == nil {
+							dnsConfig = network.DnsConfig
+						}
+						if routeConfig == nil {
+							routeConfig = network.IpRouteConfig
+						}
+					}
+
+					nstags["netstack"] = stack.Key
+					nsfields["name"] = stack.Name
+					nsfields["congestion_control"] = stack.CongestionControlAlgorithm
+					nsfields["max_connections"] = stack.RequestedMaxNumberOfConnections
+					nsfields["ipv6_enabled"] = stack.IpV6Enabled != nil && *stack.IpV6Enabled
+
+					nsfields["dhcp"] = false
+					nsfields["dns_servers"] = ""
+					nsfields["domain"] = ""
+					nsfields["hostname"] = ""
+					nsfields["search_domains"] = ""
+					if dnsConfig != nil {
+						dns := dnsConfig.GetHostDnsConfig()
+						nsfields["dhcp"] = dns.Dhcp
+						nsfields["dns_servers"] = strings.Join(dns.Address, ",")
+						nsfields["domain"] = dns.DomainName
+						nsfields["hostname"] = dns.HostName
+						nsfields["search_domains"] = strings.Join(dns.SearchDomain, ",")
+					}
+
+					nsfields["default_gateway"] = ""
+					nsfields["gateway_device"] = ""
+					nsfields["ipv6_default_gateway"] = ""
+					if routeConfig != nil {
+						route := routeConfig.GetHostIpRouteConfig()
+						nsfields["default_gateway"] = route.DefaultGateway
+						nsfields["gateway_device"] = route.GatewayDevice
+						nsfields["ipv6_default_gateway"] = route.IpV6DefaultGateway
+					}
+
+					acc.AddFields("vcstat_host_netstack", nsfields, nstags, t)
+				}
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostVswitch gathers host standard virtual switches and portgroups info
+func (c *VcCollector) CollectHostVswitch(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		swtags   = make(map[string]string)
+		swfields = make(map[string]interface{})
+		pgtags   = make(map[string]string)
+		pgfields = make(map[string]interface{})
+		hsref    types.ManagedObjectReference
+		hsMos    []mo.HostSystem
+		arefs    []types.ManagedObjectReference
+		host     *object.HostSystem
+		hostSt   *hostState
+		vswitchs map[string]string
+		t        time.Time
+		err      error
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get host vswitch info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		// get connected Host reference list and split it into chunks
+		arefs = nil
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnected() {
+				continue
+			}
+			arefs = append(arefs, host.Reference())
+		}
+		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
+
+		for _, refs := range chunks {
+			hsMos = nil
+			err = c.coll.Retrieve(
+				ctx,
+				refs,
+				[]string{"name", "config.network.vswitch", "config.network.portgroup"},
+				&hsMos,
+			)
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not retrieve vswitch info for host reference list: %w", err),
+				)
+				continue
+			}
+			t = time.Now()
+
+			for _, hsMo := range hsMos {
+				hsref = hsMo.Self.Reference()
+				if host = c.getHostObjectFromReference(i, &hsref); host == nil {
+					continue
+				}
+				if hsMo.Config == nil || hsMo.Config.Network == nil {
+					continue
+				}
+				network := hsMo.Config.Network
+
+				swtags["clustername"] = c.getClusternameFromHost(i, host)
+				swtags["dcname"] = dc.Name()
+				swtags["esxhostname"] = hsMo.Name
+				swtags["moid"] = hsMo.Self.Value
+				swtags["vcenter"] = c.client.Client.URL().Host
+
+				vswitchs = make(map[string]string, len(network.Vswitch))
+				for _, vswitch := range network.Vswitch {
+					vswitchs[vswitch.Key] = vswitch.Name
+
+					swtags["vswitch"] = vswitch.Name
+					swfields["mtu"] = vswitch.Mtu
+					swfields["num_ports"] = vswitch.NumPorts
+					swfields["num_ports_available"] = vswitch.NumPortsAvailable
+					swfields["used_ports"] = vswitch.NumPorts - vswitch.NumPortsAvailable
+					swfields["num_portgroups"] = len(vswitch.Portgroup)
+					swfields["uplinks"] = strings.Join(vswitchUplinks(&vswitch), ",")
+					addHostNetworkPolicyFields(swfields, vswitch.Spec.Policy)
+
+					acc.AddFields("vcstat_host_vswitch", swfields, swtags, t)
+				}
+
+				for k, v := range swtags {
+					if k != "vswitch" {
+						pgtags[k] = v
+					}
+				}
+				for _, pg := range network.Portgroup {
+					pgtags["portgroup"] = pg.Spec.Name
+					pgtags["vswitch"] = pg.Spec.VswitchName
+					if name, ok := vswitchs[pg.Vswitch]; ok {
+						pgtags["vswitch"] = name
+					}
+					pgfields["vlan_id"] = pg.Spec.VlanId
+					pgfields["used_ports"] = len(pg.Port)
+					addHostNetworkPolicyFields(pgfields, &pg.ComputedPolicy)
+
+					acc.AddFields("vcstat_host_portgroup", pgfields, pgtags, t)
+				}
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addHostNetworkPolicyFields adds standard switch teaming and security policy fields
+func addHostNetworkPolicyFields(fields map[string]interface{}, policy *types.HostNetworkPolicy) {
+	fields["teaming_policy"] = ""
+	fields["active_nics"] = ""
+	fields["standby_nics"] = ""
+	fields["allow_promiscuous"] = false
+	fields["mac_changes"] = false
+	fields["forged_transmits"] = false
+	if policy == nil {
+		return
+	}
+	if teaming := policy.NicTeaming; teaming != nil {
+		fields["teaming_policy"] = teaming.Policy
+		if teaming.NicOrder != nil {
+			fields["active_nics"] = strings.Join(teaming.NicOrder.ActiveNic, ",")
+			fields["standby_nics"] = strings.Join(teaming.NicOrder.StandbyNic, ",")
+		}
+	}
+	if security := policy.Security; security != nil {
+		fields["allow_promiscuous"] = security.AllowPromiscuous != nil && *security.AllowPromiscuous
+		fields["mac_changes"] = security.MacChanges != nil && *security.MacChanges
+		fields["forged_transmits"] = security.ForgedTransmits != nil && *security.ForgedTransmits
+	}
+}
+
+// vswitchUplinks returns the physical NIC device names of a standard virtual switch
+func vswitchUplinks(vswitch *types.HostVirtualSwitch) []string {
+	if bridge, ok := vswitch.Spec.Bridge.(*types.HostVirtualSwitchBondBridge); ok {
+		return bridge.NicDevice
+	}
+	uplinks := make([]string, 0, len(vswitch.Pnic))
+	for _, pnic := range vswitch.Pnic {
+		uplinks = append(uplinks, strings.TrimPrefix(pnic, "key-vim.host.PhysicalNic-"))
+	}
+	return uplinks
+}
@@ -0,0 +1,301 @@
+// This file contains vccollector methods to gather stats about host security posture
+//  (like lockdown mode, shell services, secure boot or TPM attestation)
+//
+// Author: Tesifonte Belda
+// License: The MIT License (MIT)
+
+package vccollector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/tesibelda/vcstat/pkg/govplus"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// host security compliance codes
+const (
+	securityCompliant    int16 = 0
+	securityUnknown      int16 = 1
+	securityNotCompliant int16 = 2
+)
+
+// CollectHostSecurity gathers host security posture info with a compliance code per
+// setting and an overall one
+func (c *VcCollector) CollectHostSecurity(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		sctags        = make(map[string]string)
+		scfields      = make(map[string]interface{})
+		hsref         types.ManagedObjectReference
+		hsMos, sbMos  []mo.HostSystem
+		svcMos        []mo.HostServiceSystem
+		arefs, srefs  []types.ManagedObjectReference
+		host          *object.HostSystem
+		hostSt        *hostState
+		services      map[string][]types.HostService
+		secureBoot    map[string]*bool
+		options       []types.BaseOptionValue
+		t             time.Time
+		err           error
+		code, overall int16
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get host security info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		// get connected Host reference list and split it into chunks
+		arefs = nil
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnected() {
+				continue
+			}
+			arefs = append(arefs, host.Reference())
+		}
+		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
+
+		for _, refs := range chunks {
+			hsMos = nil
+			err = c.coll.Retrieve(
+				ctx,
+				refs,
+				[]string{
+					"name",
+					"config.lockdownMode",
+					"summary.tpmAttestation",
+					"configManager.serviceSystem",
+					"configManager.advancedOption",
+				},
+				&hsMos,
+			)
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not retrieve security info for host reference list: %w", err),
+				)
+				continue
+			}
+
+			// get services of the hosts in this chunk
+			srefs = nil
+			for _, hsMo := range hsMos {
+				if hsMo.ConfigManager.ServiceSystem != nil {
+					srefs = append(srefs, *hsMo.ConfigManager.ServiceSystem)
+				}
+			}
+			services = make(map[string][]types.HostService)
+			if len(srefs) > 0 {
+				svcMos = nil
+				err = c.coll.Retrieve(ctx, srefs, []string{"serviceInfo.service"}, &svcMos)
+				if err != nil {
+					if exit, err := govplus.IsHardQueryError(err); exit {
+						return err
+					}
+					acc.AddError(
+						fmt.Errorf("could not retrieve info for host service reference list: %w", err),
+					)
+				}
+				for _, svcMo := range svcMos {
+					services[svcMo.Self.Value] = svcMo.ServiceInfo.Service
+				}
+			}
+
+			// UEFI secure boot is only available since vSphere 8.0U3 so
+			// older vCenters reject the property
+			secureBoot = make(map[string]*bool)
+			sbMos = nil
+			err = c.coll.Retrieve(ctx, refs, []string{"capability.uefiSecureBoot"}, &sbMos)
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+			}
+			for _, sbMo := range sbMos {
+				if sbMo.Capability != nil {
+					secureBoot[sbMo.Self.Value] = sbMo.Capability.UefiSecureBoot
+				}
+			}
+			t = time.Now()
+
+			for _, hsMo := range hsMos {
+				hsref = hsMo.Self.Reference()
+				if host = c.getHostObjectFromReference(i, &hsref); host == nil {
+					continue
+				}
+				sctags["clustername"] = c.getClusternameFromHost(i, host)
+				sctags["dcname"] = dc.Name()
+				sctags["esxhostname"] = hsMo.Name
+				sctags["moid"] = hsMo.Self.Value
+				sctags["vcenter"] = c.client.Client.URL().Host
+
+				overall = securityCompliant
+
+				// lockdown mode
+				lockdown := ""
+				if hsMo.Config != nil {
+					lockdown = string(hsMo.Config.LockdownMode)
+				}
+				code = lockdownModeComplianceCode(lockdown)
+				overall = max(overall, code)
+				scfields["lockdown_mode"] = lockdown
+				scfields["lockdown_mode_code"] = code
+
+				// SSH and ESXi Shell services
+				known, sshRunning, shellRunning := false, false, false
+				if hsMo.ConfigManager.ServiceSystem != nil {
+					hsServices, ok := services[hsMo.ConfigManager.ServiceSystem.Value]
+					known = ok
+					for _, service := range hsServices {
+						switch service.Key {
+						case "TSM-SSH":
+							sshRunning = service.Running
+						case "TSM":
+							shellRunning = service.Running
+						}
+					}
+				}
+				code = serviceComplianceCode(known, sshRunning)
+				overall = max(overall, code)
+				scfields["ssh_running"] = sshRunning
+				scfields["ssh_code"] = code
+				code = serviceComplianceCode(known, shellRunning)
+				overall = max(overall, code)
+				scfields["shell_running"] = shellRunning
+				scfields["shell_code"] = code
+
+				// UEFI secure boot as reported by the firmware at boot time,
+				// TPM attestation confirms it
+				code = securityUnknown
+				scfields["secure_boot"] = false
+				if enabled := secureBoot[hsMo.Self.Value]; enabled != nil {
+					scfields["secure_boot"] = *enabled
+					code = securityNotCompliant
+					if *enabled {
+						code = securityCompliant
+					}
+				}
+				overall = max(overall, code)
+				scfields["secure_boot_code"] = code
+
+				// TPM attestation
+				code = securityUnknown
+				scfields["tpm_attestation"] = ""
+				if tpm := hsMo.Summary.TpmAttestation; tpm != nil {
+					scfields["tpm_attestation"] = string(tpm.Status)
+					code = securityNotCompliant
+					if tpm.Status == types.HostTpmAttestationInfoAcceptanceStatusAccepted {
+						code = securityCompliant
+					}
+				}
+				overall = max(overall, code)
+				scfields["tpm_attestation_code"] = code
+
+				// ESXi Shell timeouts
+				options = nil
+				if ref := hsMo.ConfigManager.AdvancedOption; ref != nil {
+					m := object.NewOptionManager(c.client.Client, *ref)
+					options, err = m.Query(ctx, optionQueryName("UserVars.ESXiShell*"))
+					if err != nil {
+						if exit, err := govplus.IsHardQueryError(err); exit {
+							return err
+						}
+						acc.AddError(
+							fmt.Errorf("could not get %s shell timeout settings: %w", hsMo.Name, err),
+						)
+					}
+				}
+				shellTimeout := intOptionValue(options, "UserVars.ESXiShellTimeOut")
+				shellInteractiveTimeout := intOptionValue(
+					options,
+					"UserVars.ESXiShellInteractiveTimeOut",
+				)
+				code = securityUnknown
+				if shellTimeout >= 0 && shellInteractiveTimeout >= 0 {
+					code = securityNotCompliant
+					if shellTimeout > 0 && shellInteractiveTimeout > 0 {
+						code = securityCompliant
+					}
+				}
+				overall = max(overall, code)
+				scfields["shell_timeout"] = shellTimeout
+				scfields["shell_interactive_timeout"] = shellInteractiveTimeout
+				scfields["shell_timeout_code"] = code
+
+				scfields["compliance_code"] = overall
+
+				acc.AddFields("vcstat_host_security", scfields, sctags, t)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// intOptionValue returns the integer value of the given option key or -1 if not available
+func intOptionValue(options []types.BaseOptionValue, key string) int64 {
+	for _, option := range options {
+		o := option.GetOptionValue()
+		if o.Key != key {
+			continue
+		}
+		switch value := o.Value.(type) {
+		case int32:
+			return int64(value)
+		case int64:
+			return value
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// lockdownModeComplianceCode converts host lockdown mode to a compliance code
+func lockdownModeComplianceCode(mode string) int16 {
+	switch types.HostLockdownMode(mode) {
+	case types.HostLockdownModeLockdownNormal, types.HostLockdownModeLockdownStrict:
+		return securityCompliant
+	case types.HostLockdownModeLockdownDisabled:
+		return securityNotCompliant
+	default:
+		return securityUnknown
+	}
+}
+
+// serviceComplianceCode returns the compliance code of a shell service that should not run
+func serviceComplianceCode(known, running bool) int16 {
+	switch {
+	case !known:
+		return securityUnknown
+	case running:
+		return securityNotCompliant
+	default:
+		return securityCompliant
+	}
+}
@@ -0,0 +1,788 @@
+// This file contains vccollector methods to gather stats about host storage devices
+//  (like LUNs and local disks)
+//
+// Author: Tesifonte Belda
+// License: The MIT License (MIT)
+
+package vccollector
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/tesibelda/vcstat/pkg/govplus"
+
+	"github.com/vmware/govmomi/cli/esx"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CollectHostStorageDevices gathers host storage devices info
+// (like govc: host.esxcli storage core device list)
+func (c *VcCollector) CollectHostStorageDevices(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		sdtags       = make(map[string]string)
+		sdfields     = make(map[string]interface{})
+		x            *esx.Executor
+		res          *esx.Response
+		hostSt       *hostState
+		startTime, t time.Time
+		err          error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host storage devices info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"storage", "core", "device", "list"})
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "storage core device", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			t = time.Now()
+			for _, rv := range res.Values {
+				if len(rv) > 0 && len(rv["Device"]) > 0 {
+					sdtags["clustername"] = c.getClusternameFromHost(i, host)
+					sdtags["dcname"] = dc.Name()
+					sdtags["device"] = rv["Device"][0]
+					sdtags["esxhostname"] = host.Name()
+					sdtags["vcenter"] = c.client.Client.URL().Host
+
+					sdfields["display_name"] = rv.Value("DisplayName")
+					sdfields["vendor"] = rv.Value("Vendor")
+					sdfields["model"] = rv.Value("Model")
+					addEsxcliIntField(
+						acc,
+						sdfields,
+						"size",
+						rv,
+						"Size",
+						parseEsxcliMiB,
+						"storage core device",
+						host.Name(),
+					)
+					addEsxcliBoolField(
+						acc,
+						sdfields,
+						"ssd",
+						rv,
+						"IsSSD",
+						"storage core device",
+						host.Name(),
+					)
+					addEsxcliBoolField(
+						acc,
+						sdfields,
+						"local",
+						rv,
+						"IsLocal",
+						"storage core device",
+						host.Name(),
+					)
+					addEsxcliIntField(
+						acc,
+						sdfields,
+						"queue_depth",
+						rv,
+						"DeviceMaxQueueDepth",
+						parseEsxcliInt,
+						"storage core device",
+						host.Name(),
+					)
+					sdfields["status"] = rv.Value("Status")
+					sdfields["status_code"] = storageDeviceStatusCode(rv.Value("Status"))
+
+					acc.AddFields("vcstat_host_storage_device", sdfields, sdtags, t)
+				}
+			}
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostDiskSmart gathers SMART data of host local and SSD devices
+// (like govc: host.esxcli storage core device smart get -d device)
+func (c *VcCollector) CollectHostDiskSmart(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		smtags                    = make(map[string]string)
+		smfields                  = make(map[string]interface{})
+		x                         *esx.Executor
+		res, smres                *esx.Response
+		hostSt                    *hostState
+		startTime, smStartTime, t time.Time
+		err                       error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host disks SMART info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"storage", "core", "device", "list"})
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "storage core device", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			smStartTime = time.Now()
+			for _, rv := range res.Values {
+				if len(rv) == 0 || len(rv["Device"]) == 0 {
+					continue
+				}
+				if rv.Value("IsLocal") != "true" && rv.Value("IsSSD") != "true" {
+					continue
+				}
+
+				// devices without SMART support return an error, so just skip them
+				smres, err = x.Run(
+					ctx,
+					[]string{"storage", "core", "device", "smart", "get", "-d", rv["Device"][0]},
+				)
+				if err != nil {
+					if exit, err := govplus.IsHardQueryError(err); exit {
+						return err
+					}
+					continue
+				}
+
+				t = time.Now()
+				smtags["clustername"] = c.getClusternameFromHost(i, host)
+				smtags["dcname"] = dc.Name()
+				smtags["device"] = rv["Device"][0]
+				smtags["esxhostname"] = host.Name()
+				smtags["vcenter"] = c.client.Client.URL().Host
+
+				for k := range smfields {
+					delete(smfields, k)
+				}
+				for _, sv := range smres.Values {
+					if field, ok := smartParameterFields[sv.Value("Parameter")]; ok {
+						switch value := sv.Value("Value"); {
+						case value == "" || value == "N/A":
+						case field == "health_status":
+							smfields[field] = value
+						default:
+							i, err := parseEsxcliInt(value)
+							if err != nil {
+								hostExecutorParseAddError(
+									acc,
+									"storage core device smart",
+									host.Name(),
+									fmt.Errorf("%s: %w", sv.Value("Parameter"), err),
+								)
+								continue
+							}
+							smfields[field] = i
+						}
+					}
+				}
+				if len(smfields) > 0 {
+					acc.AddFields("vcstat_host_disk_smart", smfields, smtags, t)
+				}
+			}
+			hostSt.sumResponseTime(time.Since(smStartTime))
+
+			t = time.Now()
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostNVMe gathers host NVMe controllers health info
+// (like govc: host.esxcli nvme device list and nvme device log smart get -A adapter)
+func (c *VcCollector) CollectHostNVMe(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		nvtags                    = make(map[string]string)
+		nvfields                  = make(map[string]interface{})
+		x                         *esx.Executor
+		res, dvres, smres         *esx.Response
+		hostSt                    *hostState
+		startTime, nvStartTime, t time.Time
+		adapter                   string
+		err                       error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host NVMe devices info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"nvme", "device", "list"})
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "nvme device", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			nvStartTime = time.Now()
+			for _, rv := range res.Values {
+				if len(rv) == 0 || len(rv["HBAName"]) == 0 {
+					continue
+				}
+				adapter = rv["HBAName"][0]
+
+				dvres, err = x.Run(ctx, []string{"nvme", "device", "get", "-A", adapter})
+				if err != nil {
+					hostExecutorRunAddError(acc, "nvme device get", host.Name(), err)
+					if exit, err := govplus.IsHardQueryError(err); exit {
+						return err
+					}
+					continue
+				}
+				smres, err = x.Run(ctx, []string{"nvme", "device", "log", "smart", "get", "-A", adapter})
+				if err != nil {
+					hostExecutorRunAddError(acc, "nvme device log smart", host.Name(), err)
+					if exit, err := govplus.IsHardQueryError(err); exit {
+						return err
+					}
+					continue
+				}
+
+				t = time.Now()
+				nvtags["clustername"] = c.getClusternameFromHost(i, host)
+				nvtags["dcname"] = dc.Name()
+				nvtags["device"] = adapter
+				nvtags["esxhostname"] = host.Name()
+				nvtags["vcenter"] = c.client.Client.URL().Host
+
+				for k := range nvfields {
+					delete(nvfields, k)
+				}
+				nvfields["status"] = rv.Value("Status")
+				nvfields["signature"] = rv.Value("Signature")
+				if len(dvres.Values) > 0 {
+					nvfields["model"] = dvres.Values[0].Value("ModelNumber")
+					nvfields["firmware"] = dvres.Values[0].Value("FirmwareRevision")
+					nvfields["serial_number"] = dvres.Values[0].Value("SerialNumber")
+				}
+				if len(smres.Values) > 0 {
+					sm := smres.Values[0]
+					for field, key := range nvmeSmartFields {
+						addEsxcliIntField(
+							acc,
+							nvfields,
+							field,
+							sm,
+							key,
+							parseNvmeSmartInt,
+							"nvme device log smart",
+							host.Name(),
+						)
+					}
+					if len(sm["CriticalWarning"]) > 0 {
+						addEsxcliIntField(
+							acc,
+							nvfields,
+							"critical_warning",
+							sm,
+							"CriticalWarning",
+							parseNvmeCriticalWarning,
+							"nvme device log smart",
+							host.Name(),
+						)
+					} else if warning, found, err := nvmeCriticalWarningBits(sm); err != nil {
+						hostExecutorParseAddError(acc, "nvme device log smart", host.Name(), err)
+					} else if found {
+						nvfields["critical_warning"] = warning
+					}
+					// composite temperature is reported in Kelvin
+					if temp, ok := nvfields["temperature"].(int64); ok {
+						if temp > 0 {
+							nvfields["temperature"] = temp - 273
+						} else {
+							delete(nvfields, "temperature")
+						}
+					}
+				}
+
+				acc.AddFields("vcstat_host_nvme", nvfields, nvtags, t)
+			}
+			hostSt.sumResponseTime(time.Since(nvStartTime))
+
+			t = time.Now()
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostISCSI gathers host iSCSI adapters targets and sessions info
+// (like govc: host.esxcli iscsi session connection list and iscsi adapter target portal list)
+func (c *VcCollector) CollectHostISCSI(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		istags       = make(map[string]string)
+		isfields     = make(map[string]interface{})
+		adtags       = make(map[string]string)
+		adfields     = make(map[string]interface{})
+		x            *esx.Executor
+		res, ptres   *esx.Response
+		hostSt       *hostState
+		startTime, t time.Time
+		err          error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host iSCSI info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"iscsi", "session", "connection", "list"})
+			if err == nil {
+				ptres, err = x.Run(ctx, []string{"iscsi", "adapter", "target", "portal", "list"})
+			}
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "iscsi", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			// logged in sessions by adapter, target and portal address
+			sessions := make(map[string][]string)
+			for _, rv := range res.Values {
+				if len(rv) == 0 || len(rv["Target"]) == 0 || rv.Value("State") != "logged_in" {
+					continue
+				}
+				key := rv.Value("Adapter") + "/" + rv["Target"][0] + "/" +
+					iscsiAddressIP(rv.Value("RemoteAddress"))
+				sessions[key] = append(sessions[key], rv.Value("ISID"))
+			}
+
+			t = time.Now()
+			targets := make(map[string]map[string]bool)
+			for _, rv := range ptres.Values {
+				if len(rv) == 0 || len(rv["Target"]) == 0 {
+					continue
+				}
+				adapter, target := rv.Value("Adapter"), rv["Target"][0]
+				isids, connected := sessions[adapter+"/"+target+"/"+iscsiAddressIP(rv.Value("IP"))]
+				if targets[adapter] == nil {
+					targets[adapter] = make(map[string]bool)
+				}
+				targets[adapter][target] = targets[adapter][target] || connected
+
+				istags["clustername"] = c.getClusternameFromHost(i, host)
+				istags["dcname"] = dc.Name()
+				istags["device"] = adapter
+				istags["esxhostname"] = host.Name()
+				istags["portal"] = rv.Value("IP") + ":" + rv.Value("Port")
+				istags["target"] = target
+				istags["vcenter"] = c.client.Client.URL().Host
+
+				isfields["isid"] = strings.Join(isids, ",")
+				isfields["num_sessions"] = len(isids)
+				if connected {
+					isfields["session_state"] = "logged_in"
+					isfields["session_state_code"] = int16(0)
+				} else {
+					isfields["session_state"] = "no_session"
+					isfields["session_state_code"] = int16(2)
+				}
+
+				acc.AddFields("vcstat_host_iscsi_session", isfields, istags, t)
+			}
+
+			// connected targets per adapter
+			adtags["clustername"] = c.getClusternameFromHost(i, host)
+			adtags["dcname"] = dc.Name()
+			adtags["esxhostname"] = host.Name()
+			adtags["vcenter"] = c.client.Client.URL().Host
+			for adapter, adapterTargets := range targets {
+				numConnected := 0
+				for _, connected := range adapterTargets {
+					if connected {
+						numConnected++
+					}
+				}
+				adtags["device"] = adapter
+				adfields["num_targets"] = len(adapterTargets)
+				adfields["num_connected_targets"] = numConnected
+
+				acc.AddFields("vcstat_host_iscsi_adapter", adfields, adtags, t)
+			}
+
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostNFS gathers host NFS mounts info cross-referenced with vCenter datastore
+// host mounts (like govc: host.esxcli storage nfs list and storage nfs41 list)
+func (c *VcCollector) CollectHostNFS(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		nftags       = make(map[string]string)
+		nffields     = make(map[string]interface{})
+		x            *esx.Executor
+		res, res41   *esx.Response
+		hostSt       *hostState
+		dsMounts     map[string]map[string]types.HostMountInfo
+		startTime, t time.Time
+		err          error
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get host NFS info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+	if err = c.getAllDatacentersDatastores(ctx); err != nil {
+		return fmt.Errorf("could not get datastore entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		if dsMounts, err = c.getNFSDatastoresHostMounts(ctx, i); err != nil {
+			if exit, err := govplus.IsHardQueryError(err); exit {
+				return err
+			}
+			acc.AddError(fmt.Errorf("could not retrieve NFS datastores host mounts: %w", err))
+		}
+
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"storage", "nfs", "list"})
+			if err == nil {
+				res41, err = x.Run(ctx, []string{"storage", "nfs41", "list"})
+			}
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "storage nfs", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			t = time.Now()
+			nftags["clustername"] = c.getClusternameFromHost(i, host)
+			nftags["dcname"] = dc.Name()
+			nftags["esxhostname"] = host.Name()
+			nftags["vcenter"] = c.client.Client.URL().Host
+
+			hostMounts := dsMounts[host.Reference().Value]
+			seen := make(map[string]bool)
+			for version, rvs := range map[string][]esx.Values{"3": res.Values, "4.1": res41.Values} {
+				for _, rv := range rvs {
+					if len(rv) == 0 || len(rv["VolumeName"]) == 0 {
+						continue
+					}
+					volume := rv["VolumeName"][0]
+
+					nftags["version"] = version
+					nftags["volume"] = volume
+
+					remoteHost := rv.Value("Host")
+					if version == "4.1" {
+						remoteHost = strings.Join(rv["Hosts"], ",")
+					}
+					nffields["remote_host"] = remoteHost
+					nffields["share"] = rv.Value("Share")
+					for field, key := range nfsFlagFields {
+						addEsxcliBoolField(acc, nffields, field, rv, key, "storage nfs", host.Name())
+					}
+					nffields["hardware_acceleration"] = rv.Value("HardwareAcceleration") == "Supported"
+					mountInfo, ok := hostMounts[volume]
+					nffields["vc_mounted"] = ok && mountInfo.Mounted != nil && *mountInfo.Mounted
+					nffields["vc_accessible"] = ok &&
+						mountInfo.Accessible != nil && *mountInfo.Accessible
+
+					acc.AddFields("vcstat_host_nfs", nffields, nftags, t)
+					seen[volume] = true
+				}
+			}
+
+			// NFS datastores vCenter expects in this host but that are not mounted
+			delete(nffields, "remote_host")
+			delete(nffields, "share")
+			delete(nffields, "hardware_acceleration")
+			for volume, mountInfo := range hostMounts {
+				if seen[volume] {
+					continue
+				}
+				nftags["version"] = ""
+				nftags["volume"] = volume
+
+				nffields["accessible"] = false
+				nffields["mounted"] = false
+				nffields["read_only"] = mountInfo.AccessMode == string(types.HostMountModeReadOnly)
+				nffields["vc_mounted"] = mountInfo.Mounted != nil && *mountInfo.Mounted
+				nffields["vc_accessible"] = mountInfo.Accessible != nil && *mountInfo.Accessible
+
+				acc.AddFields("vcstat_host_nfs", nffields, nftags, t)
+			}
+
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// smartParameterFields maps esxcli SMART parameters to vcstat_host_disk_smart fields
+var smartParameterFields = map[string]string{
+	"Health Status":            "health_status",
+	"Media Wearout Indicator":  "media_wearout",
+	"Reallocated Sector Count": "reallocated_sectors",
+	"Power-on Hours":           "power_on_hours",
+	"Drive Temperature":        "temperature",
+	"Read Error Count":         "read_errors",
+	"Write Error Count":        "write_errors",
+}
+
+// storageDeviceStatusCode converts storage device Status to int16 for easy alerting
+func storageDeviceStatusCode(status string) int16 {
+	switch status {
+	case "on":
+		return 0
+	case "off":
+		return 1
+	case "dead", "dead timeout", "error", "not connected":
+		return 2
+	case "permanent device loss":
+		return 3
+	default:
+		return 1
+	}
+}
+
+// nfsFlagFields maps vcstat_host_nfs flag fields to esxcli storage nfs list keys
+var nfsFlagFields = map[string]string{
+	"accessible": "Accessible",
+	"mounted":    "Mounted",
+	"read_only":  "ReadOnly",
+}
+
+// iscsiAddressIP returns the IP of an iSCSI address that may include the port
+func iscsiAddressIP(address string) string {
+	if ip, _, err := net.SplitHostPort(address); err == nil {
+		return ip
+	}
+	return strings.Trim(address, "[]")
+}
+
+// nvmeSmartFields maps vcstat_host_nvme fields to esxcli nvme device log smart keys
+var nvmeSmartFields = map[string]string{
+	"available_spare":           "AvailableSpare",
+	"available_spare_threshold": "AvailableSpareThreshold",
+	"percentage_used":           "PercentageUsed",
+	"temperature":               "CompositeTemperature",
+}
+
+// parseNvmeSmartInt parses an NVMe SMART log value, which esxcli usually reports in
+// hexadecimal
+func parseNvmeSmartInt(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if hex, found := strings.CutPrefix(value, "0x"); found {
+		return strconv.ParseInt(hex, 16, 64)
+	}
+	return parseEsxcliInt(value)
+}
+
+// nvmeCriticalWarningFlags are the esxcli NVMe SMART critical warning flags in bit order
+var nvmeCriticalWarningFlags = []string{
+	"AvailableSpareSpaceBelowThreshold",
+	"TemperatureWarning",
+	"DeviceReliabilityDegraded",
+	"ReadOnlyMode",
+	"VolatileMemoryBackupDeviceFailure",
+}
+
+// nvmeCriticalWarningBits builds the NVMe SMART critical warning bit field from the esxcli
+// warning flags, returning whether any of them was found
+func nvmeCriticalWarningBits(values esx.Values) (int64, bool, error) {
+	var (
+		warning int64
+		found   bool
+	)
+
+	for bit, key := range nvmeCriticalWarningFlags {
+		if len(values[key]) == 0 {
+			continue
+		}
+		found = true
+		set, err := strconv.ParseBool(values[key][0])
+		if err != nil {
+			return 0, true, fmt.Errorf("%s: %w", key, err)
+		}
+		if set {
+			warning |= 1 << bit
+		}
+	}
+	return warning, found, nil
+}
+
+// parseNvmeCriticalWarning parses the NVMe SMART critical warning bit field (ie 00000100)
+func parseNvmeCriticalWarning(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "0x") {
+		return parseNvmeSmartInt(value)
+	}
+	return strconv.ParseInt(value, 2, 64)
+}
@@ -0,0 +1,650 @@
+// This file contains vccollector methods to gather stats about host system
+//  configuration and resources (like ramdisks, logging, software, IPMI events or
+//  advanced settings)
+//
+// Author: Tesifonte Belda
+// License: The MIT License (MIT)
+
+package vccollector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/tesibelda/vcstat/pkg/govplus"
+
+	"github.com/vmware/govmomi/cli/esx"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CollectHostRamdisk gathers host ramdisks usage
+// (like govc: host.esxcli system visorfs ramdisk list)
+func (c *VcCollector) CollectHostRamdisk(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		rdtags       = make(map[string]string)
+		rdfields     = make(map[string]interface{})
+		x            *esx.Executor
+		res          *esx.Response
+		hostSt       *hostState
+		startTime, t time.Time
+		err          error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host ramdisks info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"system", "visorfs", "ramdisk", "list"})
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "system visorfs ramdisk", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			t = time.Now()
+			for _, rv := range res.Values {
+				if len(rv) > 0 && len(rv["RamdiskName"]) > 0 {
+					rdtags["clustername"] = c.getClusternameFromHost(i, host)
+					rdtags["dcname"] = dc.Name()
+					rdtags["esxhostname"] = host.Name()
+					rdtags["mount_point"] = rv.Value("MountPoint")
+					rdtags["ramdisk"] = rv["RamdiskName"][0]
+					rdtags["vcenter"] = c.client.Client.URL().Host
+
+					// esxcli reports ramdisk sizes in KiB
+					for field, key := range ramdiskKiBFields {
+						addEsxcliIntField(
+							acc,
+							rdfields,
+							field,
+							rv,
+							key,
+							parseEsxcliKiB,
+							"system visorfs ramdisk",
+							host.Name(),
+						)
+					}
+					addEsxcliIntField(
+						acc,
+						rdfields,
+						"max_inodes",
+						rv,
+						"MaximumInodes",
+						parseEsxcliInt,
+						"system visorfs ramdisk",
+						host.Name(),
+					)
+					addEsxcliIntField(
+						acc,
+						rdfields,
+						"used_inodes",
+						rv,
+						"UsedInodes",
+						parseEsxcliInt,
+						"system visorfs ramdisk",
+						host.Name(),
+					)
+					addPercentageField(rdfields, "used_pct", "used", "maximum")
+					addPercentageField(rdfields, "inodes_used_pct", "used_inodes", "max_inodes")
+
+					acc.AddFields("vcstat_host_ramdisk", rdfields, rdtags, t)
+				}
+			}
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostLogging gathers host scratch, coredump and syslog configuration (like govc:
+// host.esxcli system coredump partition get, system coredump file get and
+// system syslog config get)
+func (c *VcCollector) CollectHostLogging(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		lgtags                = make(map[string]string)
+		lgfields              = make(map[string]interface{})
+		x                     *esx.Executor
+		cdpres, cdfres, slres *esx.Response
+		options               []types.BaseOptionValue
+		hostSt                *hostState
+		startTime, t          time.Time
+		scratch, remoteHosts  string
+		rotations, rotateSize int64
+		err                   error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host logging info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			cdpres, err = x.Run(ctx, []string{"system", "coredump", "partition", "get"})
+			if err == nil {
+				cdfres, err = x.Run(ctx, []string{"system", "coredump", "file", "get"})
+			}
+			if err == nil {
+				slres, err = x.Run(ctx, []string{"system", "syslog", "config", "get"})
+			}
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "system coredump and syslog", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+			options, err = queryHostOptions(ctx, host, "ScratchConfig.CurrentScratchLocation")
+			if err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not get scratch location for host %s: %w", host.Name(), err),
+				)
+				options = nil
+			}
+
+			t = time.Now()
+			lgtags["clustername"] = c.getClusternameFromHost(i, host)
+			lgtags["dcname"] = dc.Name()
+			lgtags["esxhostname"] = host.Name()
+			lgtags["vcenter"] = c.client.Client.URL().Host
+
+			lgfields["coredump_partition"] = ""
+			if len(cdpres.Values) > 0 {
+				lgfields["coredump_partition"] = cdpres.Values[0].Value("Active")
+			}
+			lgfields["coredump_file"] = ""
+			if len(cdfres.Values) > 0 {
+				lgfields["coredump_file"] = cdfres.Values[0].Value("Active")
+			}
+			lgfields["coredump_active"] = lgfields["coredump_partition"] != "" ||
+				lgfields["coredump_file"] != ""
+
+			// leave scratch fields out if its location is unknown
+			delete(lgfields, "scratch_location")
+			delete(lgfields, "scratch_persistent")
+			if len(options) > 0 {
+				scratch = fmt.Sprint(options[0].GetOptionValue().Value)
+				lgfields["scratch_location"] = scratch
+				lgfields["scratch_persistent"] = strings.HasPrefix(scratch, "/vmfs/volumes/")
+			}
+
+			remoteHosts, rotations, rotateSize = "", 0, 0
+			lgfields["syslog_local_persistent"] = false
+			if len(slres.Values) > 0 {
+				remoteHosts = slres.Values[0].Value("RemoteHost")
+				if remoteHosts == "<none>" {
+					remoteHosts = ""
+				}
+				rotations = esxcliInt(slres.Values[0].Value("DefaultRotations"))
+				rotateSize = esxcliInt(slres.Values[0].Value("DefaultRotationSize"))
+				lgfields["syslog_local_persistent"] = slres.Values[0].Value(
+					"LocalLogOutputIsPersistent",
+				) == "true"
+			}
+			lgfields["syslog_remote_hosts"] = remoteHosts
+			lgfields["syslog_remote_configured"] = remoteHosts != ""
+			lgfields["log_rotations"] = rotations
+			lgfields["log_rotation_size"] = rotateSize * 1024
+			lgfields["log_rotation_configured"] = rotations > 0 && rotateSize > 0
+
+			acc.AddFields("vcstat_host_logging", lgfields, lgtags, t)
+
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostSoftware gathers host image profile and installed VIBs filtered by name
+// (like govc: host.esxcli software profile get and software vib list)
+func (c *VcCollector) CollectHostSoftware(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		swtags       = make(map[string]string)
+		swfields     = make(map[string]interface{})
+		x            *esx.Executor
+		pfres, res   *esx.Response
+		hostSt       *hostState
+		startTime, t time.Time
+		err          error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host software info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			pfres, err = x.Run(ctx, []string{"software", "profile", "get"})
+			if err == nil {
+				res, err = x.Run(ctx, []string{"software", "vib", "list"})
+			}
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "software", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			t = time.Now()
+			swtags["clustername"] = c.getClusternameFromHost(i, host)
+			swtags["dcname"] = dc.Name()
+			swtags["esxhostname"] = host.Name()
+			swtags["vcenter"] = c.client.Client.URL().Host
+
+			if len(pfres.Values) > 0 && len(pfres.Values[0]["Name"]) > 0 {
+				swfields["name"] = pfres.Values[0]["Name"][0]
+				swfields["vendor"] = pfres.Values[0].Value("Vendor")
+				swfields["acceptance_level"] = pfres.Values[0].Value("AcceptanceLevel")
+
+				acc.AddFields("vcstat_host_image_profile", swfields, swtags, t)
+				delete(swfields, "name")
+			}
+
+			for _, rv := range res.Values {
+				if len(rv) == 0 || len(rv["Name"]) == 0 {
+					continue
+				}
+				if !c.filterVibs.Match(rv["Name"][0]) {
+					continue
+				}
+				swtags["vib"] = rv["Name"][0]
+
+				swfields["version"] = rv.Value("Version")
+				swfields["vendor"] = rv.Value("Vendor")
+				swfields["acceptance_level"] = rv.Value("AcceptanceLevel")
+				swfields["install_date"] = rv.Value("InstallDate")
+
+				acc.AddFields("vcstat_host_vib", swfields, swtags, t)
+			}
+			delete(swtags, "vib")
+			delete(swfields, "version")
+			delete(swfields, "install_date")
+
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostIpmiSel gathers host IPMI system event log entries newer than the last
+// one seen in the previous interval (like govc: host.esxcli hardware ipmi sel list)
+func (c *VcCollector) CollectHostIpmiSel(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		seltags                 = make(map[string]string)
+		selfields               = make(map[string]interface{})
+		x                       *esx.Executor
+		res                     *esx.Response
+		hostSt                  *hostState
+		hostHs                  *hostHistory
+		startTime, t, eventTime time.Time
+		record, lastRecord      int64
+		baseRecord              int64
+		severity                string
+		err                     error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host IPMI SEL info: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnectedAndResponding(c.skipNotRespondigFor) {
+				continue
+			}
+			startTime = time.Now()
+			if x, err = esx.NewExecutor(ctx, c.client.Client, host); err != nil {
+				hostExecutorNewAddError(acc, host.Name(), err)
+				continue
+			}
+			res, err = x.Run(ctx, []string{"hardware", "ipmi", "sel", "list"})
+			hostSt.sumResponseTime(time.Since(startTime))
+			if err != nil {
+				hostExecutorRunAddError(acc, "hardware ipmi sel", host.Name(), err)
+				hostSt.setNotResponding(true)
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				continue
+			}
+
+			// the first time a host is seen its current SEL is taken as the baseline and
+			// if the SEL was cleared since the last interval all its entries are new
+			lastRecord = 0
+			for _, rv := range res.Values {
+				if record = esxcliInt(rv.Value("RecordNumber")); record > lastRecord {
+					lastRecord = record
+				}
+			}
+			hostHs = c.getHostHistory(host.Reference().Value)
+			baseRecord = hostHs.lastSelRecord
+			if !hostHs.selInitialized {
+				baseRecord = lastRecord
+			} else if lastRecord < baseRecord {
+				baseRecord = 0
+			}
+
+			t = time.Now()
+			for _, rv := range res.Values {
+				if record = esxcliInt(rv.Value("RecordNumber")); record <= baseRecord {
+					continue
+				}
+				seltags["clustername"] = c.getClusternameFromHost(i, host)
+				seltags["dcname"] = dc.Name()
+				seltags["esxhostname"] = host.Name()
+				seltags["record"] = strconv.FormatInt(record, 10)
+				seltags["vcenter"] = c.client.Client.URL().Host
+
+				severity = ipmiSelSeverity(rv.Value("Message"))
+				selfields["sensor"] = rv.Value("SensorNumber")
+				selfields["event_type"] = rv.Value("EventType")
+				selfields["sel_type"] = rv.Value("SELType")
+				selfields["message"] = rv.Value("Message")
+				selfields["severity"] = severity
+				selfields["severity_code"] = ipmiSelSeverityCode(severity)
+
+				if eventTime, err = parseEsxcliTime(rv.Value("When")); err != nil {
+					eventTime = t
+				}
+				acc.AddFields("vcstat_host_ipmi_sel", selfields, seltags, eventTime)
+			}
+			hostHs.setLastSelRecord(lastRecord)
+
+			if t.Sub(startTime) >= c.maxResponseDuration {
+				hostSt.setNotResponding(true)
+				return fmt.Errorf("slow response from %s: %w", host.Name(), context.DeadlineExceeded)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectHostSettings gathers the configured host advanced settings values
+// (like govc: host.option.ls)
+func (c *VcCollector) CollectHostSettings(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		sttags  = make(map[string]string)
+		m       *object.OptionManager
+		options []types.BaseOptionValue
+		hostSt  *hostState
+		t       time.Time
+		err     error
+	)
+
+	if c.client == nil {
+		return fmt.Errorf("could not get host advanced settings: %w", govplus.ErrorNoClient)
+	}
+	if len(c.hostSettings) == 0 {
+		return nil
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		for j, host := range c.hosts[i] {
+			if !c.filterHostMatch(i, host) {
+				continue
+			}
+			if hostSt = c.getHostStateIdx(i, j); hostSt == nil {
+				acc.AddError(fmt.Errorf("could not find host state idx entry for %s", host.Name()))
+				continue
+			}
+			if !hostSt.isHostConnected() {
+				continue
+			}
+			if m, err = host.ConfigManager().OptionManager(ctx); err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not get option manager for host %s: %w", host.Name(), err),
+				)
+				continue
+			}
+			sttags["clustername"] = c.getClusternameFromHost(i, host)
+			sttags["dcname"] = dc.Name()
+			sttags["esxhostname"] = host.Name()
+			sttags["moid"] = host.Reference().Value
+			sttags["vcenter"] = c.client.Client.URL().Host
+
+			for _, key := range c.hostSettings {
+				options, err = m.Query(ctx, optionQueryName(key))
+				if err != nil {
+					if exit, err := govplus.IsHardQueryError(err); exit {
+						return err
+					}
+					acc.AddError(
+						fmt.Errorf(
+							"could not get %s advanced setting for host %s: %w",
+							key,
+							host.Name(),
+							err,
+						),
+					)
+					continue
+				}
+				t = time.Now()
+				addOptionsFields(acc, "vcstat_host_setting", key, options, sttags, t)
+			}
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// queryHostOptions returns the host advanced options matching the given name, which
+// may also be a prefix ending with a dot (ie Mem.)
+func queryHostOptions(
+	ctx context.Context,
+	host *object.HostSystem,
+	name string,
+) ([]types.BaseOptionValue, error) {
+	m, err := host.ConfigManager().OptionManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Query(ctx, name)
+}
+
+// ramdiskKiBFields maps vcstat_host_ramdisk size fields to esxcli ramdisk list keys
+var ramdiskKiBFields = map[string]string{
+	"reserved":  "Reserved",
+	"maximum":   "Maximum",
+	"used":      "Used",
+	"peak_used": "PeakUsed",
+}
+
+// addPercentageField adds the percentage of the part field in the total field, leaving it
+// out if any of them is not available
+func addPercentageField(fields map[string]interface{}, field, part, total string) {
+	delete(fields, field)
+	p, okp := fields[part].(int64)
+	t, okt := fields[total].(int64)
+	if okp && okt {
+		fields[field] = percentage(p, t)
+	}
+}
+
+// parseEsxcliKiB parses an esxcli size in KiB returning it in bytes
+func parseEsxcliKiB(value string) (int64, error) {
+	i, err := parseEsxcliInt(value)
+	return i * 1024, err
+}
+
+// percentage returns the percentage of part in total or 0 if total is 0
+func percentage(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// parseEsxcliTime parses an esxcli timestamp (ie 2023-03-01T10:22:33)
+func parseEsxcliTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02T15:04:05", value)
+	}
+	return t, err
+}
+
+// ipmiSelSeverity returns the severity of an IPMI SEL entry based on its message
+func ipmiSelSeverity(message string) string {
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "deassert"):
+		return "info"
+	case strings.Contains(msg, "critical"),
+		strings.Contains(msg, "non-recoverable"),
+		strings.Contains(msg, "uncorrectable"),
+		strings.Contains(msg, "failure"),
+		strings.Contains(msg, "ierr"):
+		return "critical"
+	case strings.Contains(msg, "assert"):
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// ipmiSelSeverityCode converts IPMI SEL entry severity to int16 for easy alerting
+func ipmiSelSeverityCode(severity string) int16 {
+	switch severity {
+	case "info":
+		return 0
+	case "warning":
+		return 2
+	case "critical":
+		return 3
+	default:
+		return 1
+	}
+}
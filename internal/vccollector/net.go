@@ -1,5 +1,5 @@
 // This file contains vccollector methods to gather stats about network entities
-//  (like Distributed Virtual Switches)
+//  (like Distributed Virtual Switches, their host members or portgroups)
 //
 // Author: Tesifonte Belda
 // License: The MIT License (MIT)
@@ -11,12 +11,14 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/telegraf"
 
 	"github.com/tesibelda/vcstat/pkg/govplus"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -29,9 +31,12 @@ func (c *VcCollector) CollectNetDVS(
 	var (
 		dvstags   = make(map[string]string)
 		dvsfields = make(map[string]interface{})
+		hstags    = make(map[string]string)
+		hsfields  = make(map[string]interface{})
 		arefs     []types.ManagedObjectReference
 		dvsMos    []mo.DistributedVirtualSwitch
 		dvsConfig *(types.DVSConfigInfo)
+		host      *object.HostSystem
 		t         time.Time
 		err       error
 		exit      bool
@@ -43,6 +48,9 @@ func (c *VcCollector) CollectNetDVS(
 	if err = c.getAllDatacentersNetworks(ctx); err != nil {
 		return fmt.Errorf("could not get network entity list: %w", err)
 	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
 
 	for i, dc := range c.dcs {
 		// get Network references and split the list into chunks
@@ -85,8 +93,180 @@ func (c *VcCollector) CollectNetDVS(
 				dvsfields["pnic_capacity_ratio_for_reservation"] = dvsConfig.PnicCapacityRatioForReservation
 				dvsfields["status"] = string(dvs.OverallStatus)
 				dvsfields["status_code"] = entityStatusCode(dvs.OverallStatus)
+				dvsfields["nioc_enabled"] = dvsConfig.NetworkResourceManagementEnabled != nil &&
+					*dvsConfig.NetworkResourceManagementEnabled
+				dvsfields["version"] = dvsConfig.ProductInfo.Version
+				dvsfields["uplinks"] = ""
+				if uplinks, ok := dvsConfig.UplinkPortPolicy.(*types.DVSNameArrayUplinkPortPolicy); ok {
+					dvsfields["uplinks"] = strings.Join(uplinks.UplinkPortName, ",")
+				}
+				dvsfields["mtu"] = int32(0)
+				dvsfields["lacp_api_version"] = ""
+				dvsfields["link_discovery_protocol"] = ""
+				dvsfields["link_discovery_operation"] = ""
+				if vmwConfig, ok := dvs.Config.(*types.VMwareDVSConfigInfo); ok {
+					dvsfields["mtu"] = vmwConfig.MaxMtu
+					dvsfields["lacp_api_version"] = vmwConfig.LacpApiVersion
+					if ldp := vmwConfig.LinkDiscoveryProtocolConfig; ldp != nil {
+						dvsfields["link_discovery_protocol"] = ldp.Protocol
+						dvsfields["link_discovery_operation"] = ldp.Operation
+					}
+				}
 
 				acc.AddFields("vcstat_net_dvs", dvsfields, dvstags, t)
+
+				// host members
+				for k, v := range dvstags {
+					hstags[k] = v
+				}
+				for _, member := range dvsConfig.Host {
+					if member.Config.Host == nil {
+						continue
+					}
+					if host = c.getHostObjectFromReference(i, member.Config.Host); host == nil {
+						continue
+					}
+					hstags["clustername"] = c.getClusternameFromHost(i, host)
+					hstags["esxhostname"] = host.Name()
+					hstags["host_moid"] = member.Config.Host.Value
+
+					hsfields["status"] = member.Status
+					hsfields["status_code"] = dvsHostMemberStatusCode(member.Status)
+					hsfields["status_detail"] = member.StatusDetail
+					hsfields["max_proxy_switch_ports"] = member.Config.MaxProxySwitchPorts
+					hsfields["current_max_proxy_switch_ports"] = int32(0)
+					if member.RuntimeState != nil {
+						hsfields["current_max_proxy_switch_ports"] =
+							member.RuntimeState.CurrentMaxProxySwitchPorts
+					}
+					hsfields["num_uplink_ports"] = len(member.UplinkPortKey)
+					hsfields["version"] = ""
+					if member.ProductInfo != nil {
+						hsfields["version"] = member.ProductInfo.Version
+					}
+
+					acc.AddFields("vcstat_net_dvs_host", hsfields, hstags, t)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CollectNetDVSHealthCheck gathers Distributed Virtual Switch health check results
+// (VLAN, MTU and teaming) per host member and uplink
+func (c *VcCollector) CollectNetDVSHealthCheck(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		hctags  = make(map[string]string)
+		arefs   []types.ManagedObjectReference
+		dvsMos  []mo.DistributedVirtualSwitch
+		host    *object.HostSystem
+		uplinks map[string]map[string]interface{}
+		teaming string
+		t       time.Time
+		err     error
+		exit    bool
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get network DVSs health check: %w", govplus.ErrorNoClient)
+	}
+	if err = c.getAllDatacentersNetworks(ctx); err != nil {
+		return fmt.Errorf("could not get network entity list: %w", err)
+	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
+
+	for i, dc := range c.dcs {
+		// get VMware DVS references and split the list into chunks
+		arefs = nil
+		for _, net := range c.nets[i] {
+			if net.Reference().Type == "VmwareDistributedVirtualSwitch" {
+				arefs = append(arefs, net.Reference())
+			}
+		}
+		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
+
+		for _, refs := range chunks {
+			dvsMos = nil
+			err = c.coll.Retrieve(ctx, refs, []string{"name", "runtime"}, &dvsMos)
+			if err != nil {
+				if exit, err = govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(
+					fmt.Errorf("could not get runtime property for DVS reference list: %w", err),
+				)
+				continue
+			}
+			t = time.Now()
+
+			for _, dvs := range dvsMos {
+				if dvs.Runtime == nil {
+					continue
+				}
+				hctags["dcname"] = dc.Name()
+				hctags["dvs"] = dvs.Name
+				hctags["moid"] = dvs.Self.Value
+				hctags["vcenter"] = c.client.Client.URL().Host
+
+				for _, member := range dvs.Runtime.HostMemberRuntime {
+					if len(member.HealthCheckResult) == 0 {
+						continue
+					}
+					if host = c.getHostObjectFromReference(i, &member.Host); host == nil {
+						continue
+					}
+					hctags["clustername"] = c.getClusternameFromHost(i, host)
+					hctags["esxhostname"] = host.Name()
+					hctags["host_moid"] = member.Host.Value
+
+					// group uplink results, teaming result applies to all host uplinks
+					uplinks = make(map[string]map[string]interface{})
+					teaming = ""
+					for _, result := range member.HealthCheckResult {
+						switch r := result.(type) {
+						case *types.VMwareDVSVlanHealthCheckResult:
+							fields := dvsHealthCheckUplinkFields(uplinks, r.UplinkPortKey)
+							fields["vlan_trunked"] = numericRangesString(r.TrunkedVlan)
+							fields["vlan_untrunked"] = numericRangesString(r.UntrunkedVlan)
+							fields["vlan_code"] = int16(0)
+							if len(r.UntrunkedVlan) > 0 {
+								fields["vlan_code"] = int16(2)
+							}
+						case *types.VMwareDVSMtuHealthCheckResult:
+							fields := dvsHealthCheckUplinkFields(uplinks, r.UplinkPortKey)
+							fields["mtu_mismatch"] = r.MtuMismatch
+							fields["vlan_not_support_switch_mtu"] = numericRangesString(
+								r.VlanNotSupportSwitchMtu,
+							)
+							fields["mtu_code"] = int16(0)
+							if r.MtuMismatch || len(r.VlanNotSupportSwitchMtu) > 0 {
+								fields["mtu_code"] = int16(2)
+							}
+						case *types.VMwareDVSTeamingHealthCheckResult:
+							teaming = r.TeamingStatus
+						}
+					}
+					if len(uplinks) == 0 && teaming != "" {
+						dvsHealthCheckUplinkFields(uplinks, "")
+					}
+
+					for key, fields := range uplinks {
+						hctags["uplink_port_key"] = key
+						if teaming != "" {
+							fields["teaming_status"] = teaming
+							fields["teaming_code"] = dvsTeamingStatusCode(teaming)
+						}
+
+						acc.AddFields("vcstat_net_dvs_healthcheck", fields, hctags, t)
+					}
+				}
 			}
 		}
 	}
@@ -151,6 +331,7 @@ func (c *VcCollector) CollectNetDVP(
 				dvpfields["num_ports"] = dvpConfig.NumPorts
 				dvpfields["status"] = string(dvp.OverallStatus)
 				dvpfields["status_code"] = entityStatusCode(dvp.OverallStatus)
+				addDVPortSettingFields(dvpfields, dvpConfig.DefaultPortConfig)
 
 				acc.AddFields("vcstat_net_dvp", dvpfields, dvptags, t)
 			}
@@ -159,3 +340,156 @@ func (c *VcCollector) CollectNetDVP(
 
 	return nil
 }
+
+// dvsHostMemberStatusCode converts DVS host member status to int16 for easy alerting
+func dvsHostMemberStatusCode(status string) int16 {
+	switch status {
+	case "up":
+		return 0
+	case "warning", "pending":
+		return 2
+	case "down", "outOfSync", "disconnected":
+		return 3
+	default:
+		return 1
+	}
+}
+
+// dvsTeamingStatusCode converts DVS teaming health check status to int16 for easy alerting
+func dvsTeamingStatusCode(status string) int16 {
+	switch status {
+	case "iphashMatch", "nonIphashMatch":
+		return 0
+	case "iphashMismatch", "nonIphashMismatch":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// dvsHealthCheckUplinkFields returns the fields map of the given uplink port key
+// creating it if needed
+func dvsHealthCheckUplinkFields(
+	uplinks map[string]map[string]interface{},
+	key string,
+) map[string]interface{} {
+	fields, ok := uplinks[key]
+	if !ok {
+		fields = make(map[string]interface{})
+		uplinks[key] = fields
+	}
+	return fields
+}
+
+// numericRangesString returns a comma separated list of ranges (ie 1-10,20)
+func numericRangesString(ranges []types.NumericRange) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Start == r.End {
+			parts = append(parts, strconv.Itoa(int(r.Start)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// addDVPortSettingFields adds distributed port VLAN, security, teaming and traffic shaping
+// policy fields
+func addDVPortSettingFields(fields map[string]interface{}, config types.BaseDVPortSetting) {
+	var (
+		setting    *types.DVPortSetting
+		vmwSetting *types.VMwareDVSPortSetting
+		ok         bool
+	)
+
+	fields["blocked"] = false
+	fields["vlan_type"] = ""
+	fields["vlan_id"] = int32(0)
+	fields["vlan_trunk"] = ""
+	fields["allow_promiscuous"] = false
+	fields["mac_changes"] = false
+	fields["forged_transmits"] = false
+	fields["teaming_policy"] = ""
+	fields["active_uplinks"] = ""
+	fields["standby_uplinks"] = ""
+	if config != nil {
+		setting = config.GetDVPortSetting()
+		fields["blocked"] = boolPolicyValue(setting.Blocked)
+		addDVSTrafficShapingFields(fields, "in", setting.InShapingPolicy)
+		addDVSTrafficShapingFields(fields, "out", setting.OutShapingPolicy)
+	} else {
+		addDVSTrafficShapingFields(fields, "in", nil)
+		addDVSTrafficShapingFields(fields, "out", nil)
+	}
+	if vmwSetting, ok = config.(*types.VMwareDVSPortSetting); !ok {
+		return
+	}
+
+	switch vlan := vmwSetting.Vlan.(type) {
+	case *types.VmwareDistributedVirtualSwitchVlanIdSpec:
+		fields["vlan_type"] = "vlan"
+		fields["vlan_id"] = vlan.VlanId
+	case *types.VmwareDistributedVirtualSwitchTrunkVlanSpec:
+		fields["vlan_type"] = "trunk"
+		fields["vlan_trunk"] = numericRangesString(vlan.VlanId)
+	case *types.VmwareDistributedVirtualSwitchPvlanSpec:
+		fields["vlan_type"] = "pvlan"
+		fields["vlan_id"] = vlan.PvlanId
+	}
+
+	// MAC management policy replaces security policy since vSphere 6.7
+	if mac := vmwSetting.MacManagementPolicy; mac != nil {
+		fields["allow_promiscuous"] = mac.AllowPromiscuous != nil && *mac.AllowPromiscuous
+		fields["mac_changes"] = mac.MacChanges != nil && *mac.MacChanges
+		fields["forged_transmits"] = mac.ForgedTransmits != nil && *mac.ForgedTransmits
+	} else if sec := vmwSetting.SecurityPolicy; sec != nil {
+		fields["allow_promiscuous"] = boolPolicyValue(sec.AllowPromiscuous)
+		fields["mac_changes"] = boolPolicyValue(sec.MacChanges)
+		fields["forged_transmits"] = boolPolicyValue(sec.ForgedTransmits)
+	}
+
+	if teaming := vmwSetting.UplinkTeamingPolicy; teaming != nil {
+		if teaming.Policy != nil {
+			fields["teaming_policy"] = teaming.Policy.Value
+		}
+		if order := teaming.UplinkPortOrder; order != nil {
+			fields["active_uplinks"] = strings.Join(order.ActiveUplinkPort, ",")
+			fields["standby_uplinks"] = strings.Join(order.StandbyUplinkPort, ",")
+		}
+	}
+}
+
+// addDVSTrafficShapingFields adds traffic shaping fields with the given direction prefix
+func addDVSTrafficShapingFields(
+	fields map[string]interface{},
+	direction string,
+	policy *types.DVSTrafficShapingPolicy,
+) {
+	var (
+		enabled              bool
+		average, peak, burst int64
+	)
+
+	if policy != nil {
+		enabled = boolPolicyValue(policy.Enabled)
+		if policy.AverageBandwidth != nil {
+			average = policy.AverageBandwidth.Value
+		}
+		if policy.PeakBandwidth != nil {
+			peak = policy.PeakBandwidth.Value
+		}
+		if policy.BurstSize != nil {
+			burst = policy.BurstSize.Value
+		}
+	}
+	fields[direction+"_shaping_enabled"] = enabled
+	fields[direction+"_shaping_average_bandwidth"] = average
+	fields[direction+"_shaping_peak_bandwidth"] = peak
+	fields[direction+"_shaping_burst_size"] = burst
+}
+
+// boolPolicyValue returns the value of a bool policy or false if not set
+func boolPolicyValue(policy *types.BoolPolicy) bool {
+	return policy != nil && policy.Value != nil && *policy.Value
+}
@@ -14,23 +14,31 @@ import (
 
 	"github.com/tesibelda/vcstat/pkg/govplus"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
-// CollectDatastoresInfo gathers info for all datastores in the datacenter
+// CollectDatastoresInfo gathers info and host mounts for all datastores in the datacenter
 // (like govc datastore.info)
 func (c *VcCollector) CollectDatastoresInfo(
 	ctx context.Context,
 	acc telegraf.Accumulator,
 ) error {
 	var (
-		dstags   = make(map[string]string)
-		dsfields = make(map[string]interface{})
-		dsMos    []mo.Datastore
-		arefs    []types.ManagedObjectReference
-		t        time.Time
-		err      error
+		dstags                              = make(map[string]string)
+		dsfields                            = make(map[string]interface{})
+		mttags                              = make(map[string]string)
+		mtfields                            = make(map[string]interface{})
+		dsMos                               []mo.Datastore
+		arefs                               []types.ManagedObjectReference
+		host                                *object.HostSystem
+		mountInfo                           *types.HostMountInfo
+		vmfs                                *types.HostVmfsVolume
+		iorm                                *types.StorageIORMInfo
+		t                                   time.Time
+		err                                 error
+		numHostsMounted, numHostsAccessible int
 	)
 
 	if c.client == nil || c.coll == nil {
@@ -39,6 +47,9 @@ func (c *VcCollector) CollectDatastoresInfo(
 	if err = c.getAllDatacentersDatastores(ctx); err != nil {
 		return fmt.Errorf("could not get datastore entity list: %w", err)
 	}
+	if err = c.getAllDatacentersClustersAndHosts(ctx); err != nil {
+		return fmt.Errorf("could not get cluster and host entity list: %w", err)
+	}
 
 	for i, dc := range c.dcs {
 		// get DS references and split the list into chunks
@@ -48,7 +59,12 @@ func (c *VcCollector) CollectDatastoresInfo(
 		chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
 
 		for _, refs := range chunks {
-			err = c.coll.Retrieve(ctx, refs, []string{"summary"}, &dsMos)
+			err = c.coll.Retrieve(
+				ctx,
+				refs,
+				[]string{"summary", "host", "info", "iormConfiguration"},
+				&dsMos,
+			)
 			if err != nil {
 				if exit, err := govplus.IsHardQueryError(err); exit {
 					return err
@@ -61,6 +77,10 @@ func (c *VcCollector) CollectDatastoresInfo(
 			t = time.Now()
 
 			for _, ds := range dsMos {
+				for k := range dsfields {
+					delete(dsfields, k)
+				}
+
 				dstags["dcname"] = dc.Name()
 				dstags["dsname"] = ds.Summary.Name
 				dstags["moid"] = ds.Self.Reference().Value
@@ -72,6 +92,54 @@ func (c *VcCollector) CollectDatastoresInfo(
 				dsfields["freespace"] = ds.Summary.FreeSpace
 				dsfields["maintenance_mode"] = ds.Summary.MaintenanceMode
 				dsfields["uncommitted"] = ds.Summary.Uncommitted
+				if vmfsInfo, ok := ds.Info.(*types.VmfsDatastoreInfo); ok && vmfsInfo.Vmfs != nil {
+					vmfs = vmfsInfo.Vmfs
+					dsfields["vmfs_version"] = vmfs.Version
+					dsfields["vmfs_major_version"] = vmfs.MajorVersion
+					dsfields["block_size_mb"] = vmfs.BlockSizeMb
+					dsfields["num_extents"] = len(vmfs.Extent)
+					dsfields["ssd"] = vmfs.Ssd != nil && *vmfs.Ssd
+					dsfields["local"] = vmfs.Local != nil && *vmfs.Local
+					dsfields["unmap_priority"] = vmfs.UnmapPriority
+				}
+				if iorm = ds.IormConfiguration; iorm != nil {
+					dsfields["sioc_enabled"] = iorm.Enabled
+					dsfields["sioc_congestion_threshold"] = iorm.CongestionThreshold
+					dsfields["sioc_congestion_threshold_mode"] = iorm.CongestionThresholdMode
+				}
+
+				// host mounts of the datastore
+				numHostsMounted, numHostsAccessible = 0, 0
+				for _, dsHost := range ds.Host {
+					mountInfo = &dsHost.MountInfo
+					if mountInfo.Mounted != nil && *mountInfo.Mounted {
+						numHostsMounted++
+					}
+					if mountInfo.Accessible != nil && *mountInfo.Accessible {
+						numHostsAccessible++
+					}
+					if host = c.getHostObjectFromReference(i, &dsHost.Key); host == nil {
+						continue
+					}
+					if !c.filterHostMatch(i, host) {
+						continue
+					}
+
+					mttags["clustername"] = c.getClusternameFromHost(i, host)
+					mttags["dcname"] = dc.Name()
+					mttags["dsname"] = ds.Summary.Name
+					mttags["esxhostname"] = host.Name()
+					mttags["vcenter"] = c.client.Client.URL().Host
+
+					mtfields["access_mode"] = mountInfo.AccessMode
+					mtfields["accessible"] = mountInfo.Accessible != nil && *mountInfo.Accessible
+					mtfields["inaccessible_reason"] = mountInfo.InaccessibleReason
+					mtfields["mounted"] = mountInfo.Mounted != nil && *mountInfo.Mounted
+
+					acc.AddFields("vcstat_datastore_host_mount", mtfields, mttags, t)
+				}
+				dsfields["num_hosts_mounted"] = numHostsMounted
+				dsfields["num_hosts_accessible"] = numHostsAccessible
 
 				acc.AddFields("vcstat_datastore", dsfields, dstags, t)
 			}
@@ -80,3 +148,48 @@ func (c *VcCollector) CollectDatastoresInfo(
 
 	return nil
 }
+
+// getNFSDatastoresHostMounts returns the host mounts of the NFS datastores in the given
+// datacenter index, indexed by host moid and datastore name
+func (c *VcCollector) getNFSDatastoresHostMounts(
+	ctx context.Context,
+	dcindex int,
+) (map[string]map[string]types.HostMountInfo, error) {
+	var (
+		mounts = make(map[string]map[string]types.HostMountInfo)
+		dsMos  []mo.Datastore
+		arefs  []types.ManagedObjectReference
+		err    error
+	)
+
+	if len(c.dss) <= dcindex {
+		return mounts, nil
+	}
+	for _, ds := range c.dss[dcindex] {
+		arefs = append(arefs, ds.Reference())
+	}
+	chunks := chunckMoRefSlice(arefs, c.queryBulkSize)
+
+	for _, refs := range chunks {
+		err = c.coll.Retrieve(ctx, refs, []string{"summary", "host"}, &dsMos)
+		if err != nil {
+			return nil, err
+		}
+		for _, ds := range dsMos {
+			switch ds.Summary.Type {
+			case string(types.HostFileSystemVolumeFileSystemTypeNFS),
+				string(types.HostFileSystemVolumeFileSystemTypeNFS41):
+			default:
+				continue
+			}
+			for _, dsHost := range ds.Host {
+				if mounts[dsHost.Key.Value] == nil {
+					mounts[dsHost.Key.Value] = make(map[string]types.HostMountInfo)
+				}
+				mounts[dsHost.Key.Value][ds.Summary.Name] = dsHost.MountInfo
+			}
+		}
+	}
+
+	return mounts, nil
+}
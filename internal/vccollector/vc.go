@@ -14,7 +14,10 @@ import (
 
 	"github.com/tesibelda/vcstat/pkg/govplus"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // CollectVcenterInfo gathers basic vcenter info
@@ -52,3 +55,72 @@ func (c *VcCollector) CollectVcenterInfo(
 
 	return nil
 }
+
+// CollectVcenterSettings gathers the configured vCenter advanced settings values and
+// the statistics intervals configuration (like govc: option.ls and metric.interval.info)
+func (c *VcCollector) CollectVcenterSettings(
+	ctx context.Context,
+	acc telegraf.Accumulator,
+) error {
+	var (
+		sttags   = make(map[string]string)
+		stfields = make(map[string]interface{})
+		cli      *vim25.Client
+		m        *object.OptionManager
+		options  []types.BaseOptionValue
+		perfMo   mo.PerformanceManager
+		t        time.Time
+		err      error
+	)
+
+	if c.client == nil || c.coll == nil {
+		return fmt.Errorf("could not get vcenter settings: %w", govplus.ErrorNoClient)
+	}
+	cli = c.client.Client
+	sttags["vcenter"] = cli.URL().Host
+
+	// advanced settings
+	if cli.ServiceContent.Setting != nil {
+		m = object.NewOptionManager(cli, *cli.ServiceContent.Setting)
+		for _, key := range c.vcSettings {
+			if options, err = m.Query(ctx, optionQueryName(key)); err != nil {
+				if exit, err := govplus.IsHardQueryError(err); exit {
+					return err
+				}
+				acc.AddError(fmt.Errorf("could not get %s vcenter setting: %w", key, err))
+				continue
+			}
+			t = time.Now()
+			addOptionsFields(acc, "vcstat_vcenter_setting", key, options, sttags, t)
+		}
+	}
+
+	// statistics intervals
+	if cli.ServiceContent.PerfManager != nil {
+		err = c.coll.RetrieveOne(
+			ctx,
+			*cli.ServiceContent.PerfManager,
+			[]string{"historicalInterval"},
+			&perfMo,
+		)
+		if err != nil {
+			if exit, err := govplus.IsHardQueryError(err); exit {
+				return err
+			}
+			acc.AddError(fmt.Errorf("could not get vcenter statistics intervals: %w", err))
+			return nil
+		}
+		t = time.Now()
+		for _, interval := range perfMo.HistoricalInterval {
+			sttags["interval"] = interval.Name
+			stfields["enabled"] = interval.Enabled
+			stfields["length"] = interval.Length
+			stfields["level"] = interval.Level
+			stfields["sampling_period"] = interval.SamplingPeriod
+
+			acc.AddFields("vcstat_vcenter_stats_interval", stfields, sttags, t)
+		}
+	}
+
+	return nil
+}
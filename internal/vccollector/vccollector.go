@@ -11,9 +11,12 @@ package vccollector
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 
@@ -33,11 +36,16 @@ type VcCollector struct {
 	coll                *property.Collector
 	filterClusters      filter.Filter
 	filterHosts         filter.Filter
+	filterVibs          filter.Filter
 	filterVms           filter.Filter
 	maxResponseDuration time.Duration
 	dataDuration        time.Duration
 	skipNotRespondigFor time.Duration
 	queryBulkSize       int
+	quickStats          bool
+	hostSettings        []string
+	vcSettings          []string
+	dvPortDetails       bool
 	VcCache
 }
 
@@ -62,6 +70,9 @@ func New(
 	if err = vcc.SetFilterVms(nil, nil); err != nil {
 		return nil, err
 	}
+	if err = vcc.SetFilterVibs(nil, nil); err != nil {
+		return nil, err
+	}
 	vcc.TLSCA = clicfg.TLSCA
 	vcc.InsecureSkipVerify = clicfg.InsecureSkipVerify
 
@@ -111,6 +122,23 @@ func (c *VcCollector) SetFilterVms(include []string, exclude []string) error {
 	return nil
 }
 
+// SetFilterVibs sets host VIBs include and exclude filters
+func (c *VcCollector) SetFilterVibs(include []string, exclude []string) error {
+	var err error
+
+	c.filterVibs, err = filter.NewIncludeExcludeFilter(include, exclude)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetHostSettings sets host advanced settings keys to gather, a key ending with *
+// gathers all settings with that prefix
+func (c *VcCollector) SetHostSettings(keys []string) {
+	c.hostSettings = keys
+}
+
 // SetMaxResponseTime sets max response time to consider an esxcli command as notresponding
 func (c *VcCollector) SetMaxResponseTime(du time.Duration) {
 	c.maxResponseDuration = du
@@ -121,6 +149,22 @@ func (c *VcCollector) SetQueryChunkSize(b int) {
 	c.queryBulkSize = b
 }
 
+// SetQuickStats sets if host and VM quickStats usage fields should be gathered
+func (c *VcCollector) SetQuickStats(enabled bool) {
+	c.quickStats = enabled
+}
+
+// SetVcenterSettings sets vCenter advanced settings keys to gather, a key ending with *
+// gathers all settings with that prefix
+func (c *VcCollector) SetVcenterSettings(keys []string) {
+	c.vcSettings = keys
+}
+
+// SetDVPortDetails sets whether to gather per port measurements of distributed switches
+func (c *VcCollector) SetDVPortDetails(details bool) {
+	c.dvPortDetails = details
+}
+
 // SetSkipHostNotRespondingDuration sets time to skip not responding to esxcli commands hosts
 func (c *VcCollector) SetSkipHostNotRespondingDuration(du time.Duration) {
 	c.skipNotRespondigFor = du
@@ -180,6 +224,55 @@ func entityStatusCode(status types.ManagedEntityStatus) int16 {
 	}
 }
 
+// optionQueryName returns the name to query in an OptionManager for the given setting key
+// that may end with * to select all settings with that prefix (ie Mem.Share*)
+func optionQueryName(key string) string {
+	prefix, found := strings.CutSuffix(key, strAsterisk)
+	if !found {
+		return key
+	}
+	// OptionManager returns all the settings below a name ending with a dot
+	if dot := strings.LastIndex(prefix, "."); dot >= 0 {
+		return prefix[:dot+1]
+	}
+	return ""
+}
+
+// addOptionsFields adds a metric per OptionManager value that matches the given key with
+// the setting key as tag and a field named after the value type
+func addOptionsFields(
+	acc telegraf.Accumulator,
+	measurement, key string,
+	options []types.BaseOptionValue,
+	tags map[string]string,
+	t time.Time,
+) {
+	prefix, wildcard := strings.CutSuffix(key, strAsterisk)
+	for _, option := range options {
+		o := option.GetOptionValue()
+		if wildcard && !strings.HasPrefix(o.Key, prefix) {
+			continue
+		}
+		if !wildcard && o.Key != key {
+			continue
+		}
+		fields := make(map[string]interface{})
+		switch value := o.Value.(type) {
+		case int32:
+			fields["value_int"] = int64(value)
+		case int64:
+			fields["value_int"] = value
+		case bool:
+			fields["value_bool"] = value
+		default:
+			fields["value_string"] = fmt.Sprint(value)
+		}
+		tags["key"] = o.Key
+		acc.AddFields(measurement, fields, tags, t)
+	}
+	delete(tags, "key")
+}
+
 // chuckMoRefSlice returns a list of lists segregating a list of oManagedObjectReference
 //
 //	into chunks with a size of chunkSize
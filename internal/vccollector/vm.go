@@ -33,6 +33,7 @@ func (c *VcCollector) CollectVmsInfo(
 		s                     *types.VirtualMachineSummary
 		r                     *types.VirtualMachineRuntimeInfo
 		k                     *types.VirtualMachineConfigSummary
+		vmSt                  *vmState
 		t                     time.Time
 		hostname, clustername string
 		err                   error
@@ -104,6 +105,28 @@ func (c *VcCollector) CollectVmsInfo(
 				vmfields["status_code"] = entityStatusCode(s.OverallStatus)
 				vmfields["template"] = k.Template
 
+				vmSt = c.getVMState(vm.Self.Reference().Value)
+				if r.BootTime != nil {
+					vmSt.setBootTime(*r.BootTime)
+					vmfields["boot_time"] = r.BootTime.Unix()
+					vmfields["uptime_seconds"] = int64(t.Sub(*r.BootTime).Seconds())
+				} else {
+					delete(vmfields, "boot_time")
+					delete(vmfields, "uptime_seconds")
+				}
+				vmfields["reboots"] = vmSt.reboots
+				if c.quickStats {
+					q := &s.QuickStats
+					vmfields["cpu_usage"] = q.OverallCpuUsage
+					vmfields["guest_mem_usage"] = int64(q.GuestMemoryUsage) * (1024 * 1024)
+					vmfields["host_mem_usage"] = int64(q.HostMemoryUsage) * (1024 * 1024)
+					vmfields["ballooned_mem"] = int64(q.BalloonedMemory) * (1024 * 1024)
+					vmfields["swapped_mem"] = int64(q.SwappedMemory) * (1024 * 1024)
+					vmfields["compressed_mem"] = q.CompressedMemory * 1024
+					vmfields["consumed_overhead_mem"] = int64(q.ConsumedOverheadMemory) *
+						(1024 * 1024)
+				}
+
 				acc.AddFields("vcstat_vm", vmfields, vmtags, t)
 			}
 		}
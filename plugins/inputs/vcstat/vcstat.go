@@ -32,25 +32,47 @@ type Config struct {
 	Timeout             config.Duration
 	IntSkipNotRespondig int16           `toml:"intervals_skip_notresponding_esxcli_hosts"`
 	QueryBulkSize       int             `toml:"query_bulk_size"`
+	QuickStats          bool            `toml:"quickstats"`
 	Log                 telegraf.Logger `toml:"-"`
 
 	ClustersExclude []string `toml:"clusters_exclude"`
 	ClustersInclude []string `toml:"clusters_include"`
 	HostsExclude    []string `toml:"hosts_exclude"`
 	HostsInclude    []string `toml:"hosts_include"`
+	VibsExclude     []string `toml:"vibs_exclude"`
+	VibsInclude     []string `toml:"vibs_include"`
 	VmsExclude      []string `toml:"vms_exclude"`
 	VmsInclude      []string `toml:"vms_include"`
 
+	HostAdvancedSettings []string `toml:"host_advanced_settings"`
+	VcenterSettings      []string `toml:"vcenter_settings"`
+
 	ClusterInstances   bool `toml:"cluster_instances"`
 	DatastoreInstances bool `toml:"datastore_instances"`
 	HostInstances      bool `toml:"host_instances"`
+	HostDiskSmart      bool `toml:"host_disk_smart_instances"`
 	HostHBAInstances   bool `toml:"host_hba_instances"`
+	HostIpmiSel        bool `toml:"host_ipmi_sel_instances"`
+	HostISCSIInstances bool `toml:"host_iscsi_instances"`
+	HostLogging        bool `toml:"host_logging_instances"`
+	HostNFSInstances   bool `toml:"host_nfs_instances"`
 	HostNICInstances   bool `toml:"host_nic_instances"`
+	HostNVMeInstances  bool `toml:"host_nvme_instances"`
 	HostFwInstances    bool `toml:"host_firewall_instances"`
 	HostGraphics       bool `toml:"host_graphics_instances"`
+	HostRamdisk        bool `toml:"host_ramdisk_instances"`
+	HostNetstack       bool `toml:"host_netstack_instances"`
+	HostSecurity       bool `toml:"host_security_instances"`
+	HostVswitch        bool `toml:"host_vswitch_instances"`
 	HostServices       bool `toml:"host_service_instances"`
+	HostSoftware       bool `toml:"host_software_instances"`
+	HostStorageDevices bool `toml:"host_storage_device_instances"`
 	NetDVSInstances    bool `toml:"net_dvs_instances"`
 	NetDVPInstances    bool `toml:"net_dvp_instances"`
+	NetDVSHealthCheck  bool `toml:"net_dvs_healthcheck_instances"`
+	NetDVPortInstances bool `toml:"net_dvport_instances"`
+	NetDVPortDetails   bool `toml:"net_dvport_details"`
+	VcenterSettingInst bool `toml:"vcenter_setting_instances"`
 	VMInstances        bool `toml:"vm_instances"`
 
 	version      string
@@ -81,6 +103,8 @@ var sampleConfig = `
   # query_bulk_size = 100
   ## number of intervals to skip esxcli commands for not responding hosts
   # intervals_skip_notresponding_esxcli_hosts = 20
+  ## add quickStats usage fields to vcstat_host and vcstat_vm measurements
+  # quickstats = false
 
   ## Filter clusters by name, default is no filtering
   ## cluster names can be specified as glob patterns
@@ -97,27 +121,73 @@ var sampleConfig = `
   # vms_include = []
   # vms_exclude = []
 
+  ## Filter host VIBs by name, default is no filtering
+  ## VIB names can be specified as glob patterns (ie ["*lpfc*", "*nvme*"])
+  # vibs_include = []
+  # vibs_exclude = []
+
+  ## Host advanced settings to collect (vcstat_host_setting), default is none
+  ## a setting key ending with * collects all the settings with that prefix
+  # host_advanced_settings = ["UserVars.SuppressShellWarning", "Mem.ShareForceSalting", "Syslog.global.*"]
+
+  ## vCenter advanced settings to collect with vcenter_setting_instances, default is none
+  ## a setting key ending with * collects all the settings with that prefix
+  # vcenter_settings = ["event.maxAge", "task.maxAge", "config.vpxd.stats.*"]
+
   #### you may enable or disable data collection per instance type ####
   ## collect cluster measurement (vcstat_cluster)
   # cluster_instances = true
-  ## collect datastore measurement (vcstat_datastore)
+  ## collect datastore measurements (vcstat_datastore, vcstat_datastore_host_mount)
   # datastore_instances = false
   ## collect host status measurement (vcstat_host)
   # host_instances = true
+  ## collect SMART data of host local and SSD disks (vcstat_host_disk_smart)
+  # host_disk_smart_instances = false
   ## collect host firewall measurement (vcstat_host_firewall)
   # host_firewall_instances = false
   ## collect host graphics measurement (vcstat_host_graphics)
   # host_graphics_instances = false
-  ## collect host bus adapter measurement (vcstat_host_hba)
+  ## collect host bus adapter measurements (vcstat_host_hba, vcstat_host_fc)
   # host_hba_instances = false
+  ## collect host IPMI system event log new entries (vcstat_host_ipmi_sel)
+  # host_ipmi_sel_instances = false
+  ## collect host iSCSI measurements (vcstat_host_iscsi_session, vcstat_host_iscsi_adapter)
+  # host_iscsi_instances = false
+  ## collect host scratch, coredump and syslog configuration (vcstat_host_logging)
+  # host_logging_instances = false
+  ## collect host NFS mounts measurement (vcstat_host_nfs)
+  # host_nfs_instances = false
+  ## collect host TCP/IP stacks DNS and routing measurement (vcstat_host_netstack)
+  # host_netstack_instances = false
   ## collect host network interface measurement (vcstat_host_nic)
   # host_nic_instances = false
+  ## collect host NVMe controller health measurement (vcstat_host_nvme)
+  # host_nvme_instances = false
+  ## collect host ramdisks usage measurement (vcstat_host_ramdisk)
+  # host_ramdisk_instances = false
+  ## collect host security posture measurement (vcstat_host_security)
+  # host_security_instances = false
   ## collect host services measurement (vcstat_host_service)
   # host_service_instances = false
-  ## collect network distributed virtual switch measurement (vcstat_net_dvs)
+  ## collect host software measurements (vcstat_host_image_profile, vcstat_host_vib)
+  # host_software_instances = false
+  ## collect host storage device measurement (vcstat_host_storage_device)
+  # host_storage_device_instances = false
+  ## collect host standard switch measurements (vcstat_host_vswitch, vcstat_host_portgroup)
+  # host_vswitch_instances = false
+  ## collect network distributed virtual switch measurements (vcstat_net_dvs, vcstat_net_dvs_host)
   # net_dvs_instances = true
+  ## collect network distributed virtual switch health check measurement (vcstat_net_dvs_healthcheck)
+  # net_dvs_healthcheck_instances = false
   ## collect network distributed virtual portgroup measurement (vcstat_net_dvp)
   # net_dvp_instances = false
+  ## collect network distributed virtual portgroup port usage measurement (vcstat_net_dvp_usage)
+  # net_dvport_instances = false
+  ## also collect per port state and counters with net_dvport_instances (vcstat_net_dvport)
+  # net_dvport_details = false
+  ## collect vCenter settings and statistics intervals measurements
+  ## (vcstat_vcenter_setting, vcstat_vcenter_stats_interval)
+  # vcenter_setting_instances = false
   ## collect virtual machine measurement (vcstat_vm)
   # vm_instances = false
 `
@@ -133,17 +203,34 @@ func init() {
 			InternalAlias:       "",
 			Timeout:             config.Duration(time.Second * 10),
 			QueryBulkSize:       100,
+			QuickStats:          false,
 			IntSkipNotRespondig: 20,
 			ClusterInstances:    true,
 			DatastoreInstances:  false,
 			HostInstances:       true,
 			HostFwInstances:     false,
 			HostGraphics:        false,
+			HostNetstack:        false,
+			HostSecurity:        false,
+			HostVswitch:         false,
 			HostServices:        false,
+			HostDiskSmart:       false,
 			HostHBAInstances:    false,
+			HostIpmiSel:         false,
+			HostISCSIInstances:  false,
+			HostLogging:         false,
+			HostNFSInstances:    false,
 			HostNICInstances:    false,
+			HostNVMeInstances:   false,
+			HostRamdisk:         false,
+			HostSoftware:        false,
+			HostStorageDevices:  false,
 			NetDVSInstances:     true,
 			NetDVPInstances:     false,
+			NetDVSHealthCheck:   false,
+			NetDVPortInstances:  false,
+			NetDVPortDetails:    false,
+			VcenterSettingInst:  false,
 			VMInstances:         false,
 			pollInterval:        time.Second * 60,
 		}
@@ -177,6 +264,10 @@ func (vcs *Config) Init() error {
 		time.Duration(vcs.pollInterval.Seconds() * float64(vcs.IntSkipNotRespondig)),
 	)
 	vcs.vcc.SetQueryChunkSize(vcs.QueryBulkSize)
+	vcs.vcc.SetQuickStats(vcs.QuickStats)
+	vcs.vcc.SetHostSettings(vcs.HostAdvancedSettings)
+	vcs.vcc.SetVcenterSettings(vcs.VcenterSettings)
+	vcs.vcc.SetDVPortDetails(vcs.NetDVPortDetails)
 	err = vcs.vcc.SetFilterClusters(vcs.ClustersInclude, vcs.ClustersExclude)
 	if err != nil {
 		return fmt.Errorf("error parsing clusters filters: %w", err)
@@ -189,6 +280,10 @@ func (vcs *Config) Init() error {
 	if err != nil {
 		return fmt.Errorf("error parsing VMs filters: %w", err)
 	}
+	err = vcs.vcc.SetFilterVibs(vcs.VibsInclude, vcs.VibsExclude)
+	if err != nil {
+		return fmt.Errorf("error parsing VIBs filters: %w", err)
+	}
 
 	_, err = url.Parse(vcs.VCenter)
 	if err != nil {
@@ -292,7 +387,10 @@ func (vcs *Config) Gather(acc telegraf.Accumulator) error {
 
 	// selfmonitoring
 	vcs.GatherTime.Set(time.Since(startTime).Nanoseconds())
-	if vcs.HostHBAInstances || vcs.HostNICInstances || vcs.HostFwInstances {
+	if vcs.HostHBAInstances || vcs.HostNICInstances || vcs.HostFwInstances ||
+		vcs.HostStorageDevices || vcs.HostDiskSmart || vcs.HostNVMeInstances ||
+		vcs.HostISCSIInstances || vcs.HostNFSInstances || vcs.HostRamdisk ||
+		vcs.HostLogging || vcs.HostSoftware || vcs.HostIpmiSel {
 		vcs.NotRespondingHosts.Set(int64(vcs.vcc.GetNumberNotRespondingHosts()))
 	}
 	for _, m := range selfstat.Metrics() {
@@ -356,6 +454,11 @@ func (vcs *Config) gatherHighLevelEntities(
 	if err = col.CollectVcenterInfo(ctx, acc); err != nil {
 		return err
 	}
+	if vcs.VcenterSettingInst {
+		if err = col.CollectVcenterSettings(ctx, acc); err != nil {
+			return err
+		}
+	}
 
 	//--- Get Datacenters info
 	if vcs.ClusterInstances || vcs.HostInstances {
@@ -403,6 +506,34 @@ func (vcs *Config) gatherHost(
 		}
 	}
 
+	if vcs.HostIpmiSel {
+		hasEsxcliCollection = true
+		if err = col.CollectHostIpmiSel(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostISCSIInstances {
+		hasEsxcliCollection = true
+		if err = col.CollectHostISCSI(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostLogging {
+		hasEsxcliCollection = true
+		if err = col.CollectHostLogging(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostNFSInstances {
+		hasEsxcliCollection = true
+		if err = col.CollectHostNFS(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	if vcs.HostNICInstances {
 		hasEsxcliCollection = true
 		if err = col.CollectHostNIC(ctx, acc); err != nil {
@@ -410,6 +541,13 @@ func (vcs *Config) gatherHost(
 		}
 	}
 
+	if vcs.HostNVMeInstances {
+		hasEsxcliCollection = true
+		if err = col.CollectHostNVMe(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	if vcs.HostFwInstances {
 		hasEsxcliCollection = true
 		if err = col.CollectHostFw(ctx, acc); err != nil {
@@ -424,12 +562,62 @@ func (vcs *Config) gatherHost(
 		}
 	}
 
+	if vcs.HostRamdisk {
+		hasEsxcliCollection = true
+		if err = col.CollectHostRamdisk(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostNetstack {
+		if err = col.CollectHostNetstack(ctx, acc); err != nil {
+			return err
+		}
+	}
+	if vcs.HostSecurity {
+		if err = col.CollectHostSecurity(ctx, acc); err != nil {
+			return err
+		}
+	}
+	if vcs.HostVswitch {
+		if err = col.CollectHostVswitch(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if len(vcs.HostAdvancedSettings) > 0 {
+		if err = col.CollectHostSettings(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	if vcs.HostServices {
 		if err = col.CollectHostServices(ctx, acc); err != nil {
 			return err
 		}
 	}
 
+	if vcs.HostSoftware {
+		hasEsxcliCollection = true
+		if err = col.CollectHostSoftware(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostStorageDevices {
+		hasEsxcliCollection = true
+		if err = col.CollectHostStorageDevices(ctx, acc); err != nil {
+			return err
+		}
+	}
+
+	if vcs.HostDiskSmart {
+		hasEsxcliCollection = true
+		if err = col.CollectHostDiskSmart(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	if hasEsxcliCollection {
 		if err = col.ReportHostEsxcliResponse(ctx, acc); err != nil {
 			return err
@@ -459,12 +647,24 @@ func (vcs *Config) gatherNetwork(
 		}
 	}
 
+	if vcs.NetDVSHealthCheck {
+		if err = col.CollectNetDVSHealthCheck(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	if vcs.NetDVPInstances {
 		if err = col.CollectNetDVP(ctx, acc); err != nil {
 			return err
 		}
 	}
 
+	if vcs.NetDVPortInstances {
+		if err = col.CollectNetDVPort(ctx, acc); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 